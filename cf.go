@@ -1,6 +1,7 @@
 package main
 
 import (
+	cln "cf/client"
 	cmd "cf/cmd"
 	cfg "cf/config"
 
@@ -15,27 +16,50 @@ Usage:
   cf config
   cf gen    [-A]
   cf open   [<info>...]
+  cf show   [<info>...]
   cf fetch  [<info>...]
-  cf test   [[-i -e<e> -t<t>] | -C] [-f<f>]
+  cf test   [[-i --abs-eps=<a> --rel-eps=<r> -j<j> -d<d> -r<r> -w --sandbox] | -C] [-t<t> -m<m>] [--stderr=<s>] [-o<o> | --failed] [-f<f>]
+  cf stress -G<gen> -B<brute> [-n<n> -i --abs-eps=<a> --rel-eps=<r> -t<t> -m<m> -d<d> -f<f> --stderr=<s> --sandbox]
+  cf add-test [-E --input=<inp>] [--answer=<ans> | --from-solution [-t<t> -f<f>]]
   cf submit [<info>... -f<f>]
   cf watch  [<info>... -s<cnt>]
   cf pull   [<info>...] -H<handle>
+  cf clean
   cf upgrade
 
 Options:
   -A, --all                   force the selection menu to appear
   -f, --file <f>              specify source file to test / submit [default: *.*]
   -i, --ignore-case           omit character-case differences in output
-  -e, --ignore-exp <e>        omit float differences < 1e-<e> [default: 10]
-  -t, --time-limit <t>        set time limit (secs) for each test case [default: 2] 
+  --abs-eps <a>               max absolute error of float tokens in output (default: problem hint, or 1e-9)
+  --rel-eps <r>               max relative error of float tokens in output (default: problem hint, or 1e-9)
+  -t, --time-limit <t>        set time limit (secs) for each test case (default: problem time limit, or 2)
+  -G, --generator <gen>       script to generate test input (run with seed as argument)
+  -B, --brute <brute>         script to run brute force solution
+  -n, --iterations <n>        maximum number of stress tests to run [default: 1000]
   -s, --submissions <cnt>     watch status of last <cnt> submissions [default: 0] 
   -H, --handle <handle>       cf handle (not email) of reqd user  
-  -C, --custom                run interactive session, with input from stdin
+  -m, --memory-limit <m>      set memory limit (MB) for each test case (default: problem memory limit, or 256)
+  -j, --jobs <j>              number of test cases to run in parallel [default: 1]
+  -d, --diff <d>              diff view of wrong output (side / unified / token / none) [default: side]
+  -r, --report <r>            print report of verdicts in format (json / junit)
+  -E, --editor                read test input / answer (not specified) from $EDITOR
+  --input <inp>               file to read test input from ('-' for stdin)
+  --answer <ans>              file to read expected output from ('-' for stdin)
+  --from-solution             find expected output by running solution
+  -o, --only <o>              run only tests with comma separated names (e.g. 0,3,5)
+  --failed                    run only tests that failed in the last run
+  -w, --watch                 re-run tests on modification of source / test files
+  --stderr <s>                stderr of solution on each test (show / hide / file) [default: show]
+  --sandbox                   run solution with resource limits (linux, single threaded, no shell)
+  -C, --custom                run interactive session, against interactor (if present)
   -h, --help                  show this screen
   -v, --version               show cli version
 `
 
 func main() {
+	// run as sandbox helper (if invoked so)
+	cln.SandboxExec()
 
 	args, _ := docopt.ParseArgs(manPage, os.Args[1:], cmd.Version)
 	// create ~/cf/ folder
@@ -47,6 +71,7 @@ func main() {
 	cfg.InitTemplates(filepath.Join(path, "templates.json"))
 	cfg.InitSettings(filepath.Join(path, "settings.json"))
 	cfg.InitSession(filepath.Join(path, "sessions.json"))
+	cfg.InitCache(filepath.Join(path, "cache"))
 	// bind data to struct holding flags
 	// and extract contest type / path
 	opt := cmd.Opts{}
@@ -61,16 +86,24 @@ func main() {
 		opt.RunGen()
 	case opt.Open:
 		opt.RunOpen()
+	case opt.Show:
+		opt.RunShow()
 	case opt.Fetch:
 		opt.RunFetch()
 	case opt.Test:
 		opt.RunTest()
+	case opt.Stress:
+		opt.RunStress()
+	case opt.AddTest:
+		opt.RunAddTest()
 	case opt.Submit:
 		opt.RunSubmit()
 	case opt.Watch:
 		opt.RunWatch()
 	case opt.Pull:
 		opt.RunPull()
+	case opt.Clean:
+		cmd.RunClean()
 	case opt.Upgrade:
 		cmd.RunUpgrade()
 	}
@@ -88,8 +121,15 @@ Global variables
 	- ${contest}            : The contest id parsed from args / folder path
 	- ${problem}            : The problem id parsed from args / folder path
 	- ${group}              : The group id parsed from folder path / url
-	- ${contClass}          : The class of the contest (contest / gym / group)
+	- ${contClass}          : The class of the contest (contest / gym / group / acmsguru)
 	- ${idx}                : index of iteration (eg: c${idx} as name of gen file)
 	- ${file}               : file you wish to test / submit
 	- ${fileBase}           : file path (without extension) you wish to test / submit
+
+	Problem data (in gen, from fetched problem.json):
+	- ${name}               : name of the problem
+	- ${timeLimit}          : time limit (secs) of the problem
+	- ${memLimit}           : memory limit (MB) of the problem
+	- ${inputFile}          : input file of the problem ('standard input' if none)
+	- ${outputFile}         : output file of the problem ('standard output' if none)
 */
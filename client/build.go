@@ -0,0 +1,117 @@
+package cln
+
+import (
+	cfg "cf/config"
+
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BuildKey returns hash of data of source file, template
+// alias and the (compilation) script, to key build cache with
+func BuildKey(file, alias, script string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, val := range [][]byte{data, []byte(alias), []byte(script)} {
+		hash.Write(val)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ExecBuild runs (compilation) script and caches files created /
+// modified by it under key. If build of key is already cached, the
+// cached files are restored instead. Builds with no files found
+// (output outside current directory) aren't cached. Returns output
+// of script and whether the cached build was used
+func ExecBuild(script, key string) (string, bool, error) {
+	dir := filepath.Join(cfg.CacheDir, key)
+	manifest := filepath.Join(dir, "files.json")
+
+	// restore cached build files (if present)
+	if data, err := ioutil.ReadFile(manifest); err == nil {
+		var files []string
+		json.Unmarshal(data, &files)
+		for _, file := range files {
+			os.MkdirAll(filepath.Dir(file), os.ModePerm)
+			if err := copyFile(filepath.Join(dir, "files", file), file); err != nil {
+				return "", false, err
+			}
+		}
+		return "", true, nil
+	}
+
+	// find files created / modified by script
+	before := modTimes()
+	out, err := ExecCompile(script)
+	if err != nil {
+		return out, false, err
+	}
+	var files []string
+	for file, mod := range modTimes() {
+		if prev, ok := before[file]; ok == false || prev != mod {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		return out, false, nil
+	}
+
+	// save build files to cache (ignore failures)
+	for _, file := range files {
+		dst := filepath.Join(dir, "files", file)
+		os.MkdirAll(filepath.Dir(dst), os.ModePerm)
+		if copyFile(file, dst) != nil {
+			os.RemoveAll(dir)
+			return out, false, nil
+		}
+	}
+	data, _ := json.Marshal(files)
+	os.MkdirAll(dir, os.ModePerm)
+	ioutil.WriteFile(manifest, data, 0644)
+	return out, false, nil
+}
+
+// max depth of subdirectories searched for build files
+const buildDepth = 2
+
+// modTimes returns modification time of all files in the current
+// directory (and subdirectories upto buildDepth levels). Hidden
+// directories and the workspace aren't searched
+func modTimes() map[string]int64 {
+	data := make(map[string]int64)
+	filepath.Walk(".", func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && path != "." {
+			name := info.Name()
+			depth := strings.Count(path, string(os.PathSeparator)) + 1
+			if strings.HasPrefix(name, ".") || name == cfg.Settings.WSName || depth > buildDepth {
+				return filepath.SkipDir
+			}
+		} else if err == nil && info.Mode().IsRegular() {
+			data[path] = info.ModTime().UnixNano()
+		}
+		return nil
+	})
+	return data
+}
+
+// copyFile copies data (and permissions) of src to dst
+func copyFile(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, data, info.Mode().Perm())
+}
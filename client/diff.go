@@ -0,0 +1,227 @@
+package cln
+
+import (
+	pkg "cf/packages"
+
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+)
+
+const (
+	// max lines of input / output displayed in diff
+	maxInpLines = 20
+	maxOutLines = 50
+)
+
+// firstDiff finds the first token of out that differs from the
+// token of ans (compared with equal) and returns the line (1-based)
+// and index (0-based) of the token in the line, and the tokens.
+// Returns line = 0 if no such token exists. Missing tokens are empty
+func firstDiff(out, ans string, equal func(string, string) bool) (int, int, string, string) {
+	outLines := strings.Split(out, "\n")
+	ansLines := strings.Split(ans, "\n")
+	for i := 0; i < len(outLines) || i < len(ansLines); i++ {
+		outToks, _ := tokenize(lineOf(outLines, i))
+		ansToks, _ := tokenize(lineOf(ansLines, i))
+		for j := 0; j < len(outToks) || j < len(ansToks); j++ {
+			got, exp := lineOf(outToks, j), lineOf(ansToks, j)
+			if got == "" || exp == "" || equal(got, exp) == false {
+				return i + 1, j, got, exp
+			}
+		}
+	}
+	return 0, 0, "", ""
+}
+
+// lineOf returns data[i] (empty if out of bounds)
+func lineOf(data []string, i int) string {
+	if i < len(data) {
+		return data[i]
+	}
+	return ""
+}
+
+// tokenize splits line into space separated tokens
+// and returns the tokens with their columns (1-based)
+func tokenize(line string) ([]string, []int) {
+	var toks []string
+	var cols []int
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(line) && line[j] != ' ' && line[j] != '\t' {
+			j++
+		}
+		toks = append(toks, line[i:j])
+		cols = append(cols, i+1)
+		i = j
+	}
+	return toks, cols
+}
+
+// DiffSummary returns summary of the first difference of
+// out and ans, for example "line 3 col 5: expected 42, got 41"
+func DiffSummary(out, ans string, equal func(string, string) bool) string {
+	line, idx, got, exp := firstDiff(out, ans, equal)
+	if line == 0 {
+		return "outputs are equal"
+	}
+	// column of token (end of line, if token is missing)
+	str := lineOf(strings.Split(out, "\n"), line-1)
+	_, cols := tokenize(str)
+	col := len(str) + 1
+	if idx < len(cols) {
+		col = cols[idx]
+	}
+
+	quote := func(tok string) string {
+		if tok == "" {
+			return "nothing"
+		}
+		return tok
+	}
+	return fmt.Sprintf("line %d col %d: expected %v, got %v", line, col, quote(exp), quote(got))
+}
+
+// highlight returns line, with token of index idx colored
+// (marker '_' is appended, if no such token exists)
+func highlight(line string, idx int) string {
+	toks, cols := tokenize(line)
+	if idx >= len(toks) {
+		return line + pkg.Red.Sprint("_")
+	}
+	start, end := cols[idx]-1, cols[idx]-1+len(toks[idx])
+	return line[:start] + pkg.Red.Sprint(line[start:end]) + line[end:]
+}
+
+// truncate returns max lines of data starting from line start,
+// with "... N more lines" marking the lines omitted before / after
+func truncate(data string, start, max int) []string {
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
+	if start > len(lines) {
+		start = len(lines)
+	}
+	var res []string
+	if start > 0 {
+		res = append(res, fmt.Sprintf("... %d more lines", start))
+	}
+	if len(lines)-start > max {
+		res = append(res, lines[start:start+max]...)
+		return append(res, fmt.Sprintf("... %d more lines", len(lines)-start-max))
+	}
+	return append(res, lines[start:]...)
+}
+
+// lineDiff returns unified diff of lines of out and ans, based on
+// their longest common subsequence. Lines of ans only are marked
+// with '-' (expected), and lines of out only with '+' (actual)
+func lineDiff(out, ans []string) []string {
+	n, m := len(out), len(ans)
+	// lcs[i][j] is length of common subsequence of out[i:] and ans[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if out[i] == ans[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] > lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var res []string
+	for i, j := 0, 0; i < n || j < m; {
+		switch {
+		case i < n && j < m && out[i] == ans[j]:
+			res = append(res, " "+out[i])
+			i, j = i+1, j+1
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			res = append(res, pkg.Red.Sprint("-"+ans[j]))
+			j++
+		default:
+			res = append(res, pkg.Green.Sprint("+"+out[i]))
+			i++
+		}
+	}
+	return res
+}
+
+// PrintDiff is run if outputs don't match
+// returns input data, and then the diff of => out vs ans
+// Format of diff is based on mode (side / unified / token / none)
+func PrintDiff(inp, out, ans, mode string, equal func(string, string) bool) string {
+	// variable to hold diff output
+	var diff strings.Builder
+	headerfmt := color.New(color.FgBlue, color.Underline).SprintfFunc()
+	// print summary of first difference
+	fmt.Fprintln(&diff, DiffSummary(out, ans, equal))
+	if mode == "none" {
+		return diff.String()
+	}
+
+	// print (truncated) input data
+	fmt.Fprintln(&diff, headerfmt("Input"))
+	fmt.Fprintln(&diff, strings.Join(truncate(inp, 0, maxInpLines), "\n"))
+
+	// break output into lines, displaying lines
+	// from few lines before the first difference
+	start, _, _, _ := firstDiff(out, ans, equal)
+	if start -= 4; start < 0 {
+		start = 0
+	}
+	str1 := truncate(out, start, maxOutLines)
+	str2 := truncate(ans, start, maxOutLines)
+
+	switch mode {
+	case "unified":
+		// print expected (-) and actual (+) of differing lines
+		fmt.Fprintln(&diff, headerfmt("Diff (-expected +actual)"))
+		for _, line := range lineDiff(str1, str2) {
+			fmt.Fprintln(&diff, line)
+		}
+
+	case "token":
+		// print first differing line, highlighting the token
+		line, idx, _, _ := firstDiff(out, ans, equal)
+		if line == 0 {
+			break
+		}
+		fmt.Fprintln(&diff, headerfmt(fmt.Sprintf("Line %d", line)))
+		outLine := lineOf(strings.Split(out, "\n"), line-1)
+		ansLine := lineOf(strings.Split(ans, "\n"), line-1)
+		fmt.Fprintln(&diff, "Actual:   "+highlight(outLine, idx))
+		fmt.Fprintln(&diff, "Expected: "+highlight(ansLine, idx))
+
+	default:
+		// print output diff data
+		tbl := uitable.New()
+		tbl.Separator = " | "
+
+		// equalize string lengths
+		if len(str1) < len(str2) {
+			str1 = append(str1, make([]string, len(str2)-len(str1))...)
+		} else {
+			str2 = append(str2, make([]string, len(str1)-len(str2))...)
+		}
+		tbl.AddRow(headerfmt("Actual Output"), headerfmt("Expected Output"))
+		// iterate over every row of outputs
+		for i := 0; i < len(str1); i++ {
+			tbl.AddRow(str1[i], str2[i])
+		}
+		fmt.Fprintln(&diff, tbl)
+	}
+	fmt.Fprintln(&diff)
+
+	return diff.String()
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+// +build !windows
+
+package cln
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// description of signals commonly terminating solutions
+var signals = map[syscall.Signal]string{
+	syscall.SIGSEGV: "SIGSEGV (invalid memory access / stack overflow)",
+	syscall.SIGFPE:  "SIGFPE (division by zero)",
+	syscall.SIGABRT: "SIGABRT (failed assertion / uncaught exception)",
+	syscall.SIGBUS:  "SIGBUS (misaligned memory access)",
+	syscall.SIGILL:  "SIGILL (illegal instruction)",
+	syscall.SIGKILL: "SIGKILL (killed)",
+}
+
+// ExitReason describes why script exited with err, based on
+// the signal which terminated it (if any) or its exit code
+func ExitReason(err error) string {
+	exitErr, ok := err.(*exec.ExitError)
+	if ok == false {
+		return err.Error()
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if ok == true && status.Signaled() == true {
+		if desc, ok := signals[status.Signal()]; ok {
+			return "Killed by " + desc
+		}
+		return fmt.Sprintf("Killed by signal %d (%v)", status.Signal(), status.Signal())
+	}
+	return fmt.Sprintf("Exit code %d", exitErr.ExitCode())
+}
@@ -0,0 +1,33 @@
+package cln
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// description of exception codes commonly terminating solutions
+var exceptions = map[uint32]string{
+	0xC0000005: "access violation (invalid memory access)",
+	0xC00000FD: "stack overflow",
+	0xC0000094: "integer division by zero",
+	0xC000008E: "float division by zero",
+	0xC0000409: "stack buffer overrun / abort",
+	0xC0000374: "heap corruption",
+}
+
+// ExitReason describes why script exited with err,
+// based on its exit (or exception) code
+func ExitReason(err error) string {
+	exitErr, ok := err.(*exec.ExitError)
+	if ok == false {
+		return err.Error()
+	}
+	code := uint32(exitErr.ExitCode())
+	if desc, ok := exceptions[code]; ok {
+		return fmt.Sprintf("Exit code 0x%X (%v)", code, desc)
+	} else if code == 3 {
+		// exit code of abort() (failed assertions)
+		return "Exit code 3 (abort / failed assertion)"
+	}
+	return fmt.Sprintf("Exit code %d", code)
+}
@@ -6,9 +6,14 @@ import (
 
 	"bytes"
 	"fmt"
+	"math"
 	"net/url"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -54,12 +59,19 @@ func FetchProbs(contest string, link url.URL) ([]string, error) {
 	return probs, nil
 }
 
-// FetchTests extracts test cases of the problem(s) in contest
-// Returns 2d slice mapping to input and output
-// If problem == "", fetch all problem test cases
-// else, only fetch of given problem.
+// ProblemPage holds sample tests and metadata of
+// a problem, extracted from its statement
+type ProblemPage struct {
+	Inputs, Outputs []string
+	Data            ProblemData
+}
+
+// FetchTests extracts sample tests and metadata of the problem(s)
+// in contest. Returns map of problem index (parsed from header of
+// statement) to its data. If problem == "", fetch all problems
+// from problems page, else only fetch the given problem.
 // fix for https://github.com/infixint943/cf/pull/2#issuecomment-626122011
-func FetchTests(contest, problem string, link url.URL) ([][]string, [][]string, error) {
+func FetchTests(contest, problem string, link url.URL) (map[string]ProblemPage, error) {
 
 	c := cfg.Session.Client
 	if problem == "" {
@@ -70,28 +82,116 @@ func FetchTests(contest, problem string, link url.URL) ([][]string, [][]string,
 		link.Path = path.Join(link.Path, "problem", problem)
 	}
 
+	waitHost(link.Host)
 	body, err := pkg.GetReqBody(&c, link.String())
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// splInp will hold input of each problem
-	// splOut maps to splInp with the output data
-	var splInp, splOut [][]string
-	// Iterate over every problem
+	probs := make(map[string]ProblemPage)
+	// Iterate over statement of every problem
 	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(body))
-	doc.Find(".sample-test").Each(func(_ int, prob *goquery.Selection) {
+	doc.Find(".problem-statement").Each(func(_ int, stmt *goquery.Selection) {
+		// title is of the form 'A. Name'
+		title := strings.TrimSpace(stmt.Find(".header .title").Text())
+		idx := ""
+		if problem != "" {
+			idx = path.Base(problem)
+		}
+		if pos := strings.Index(title, ". "); pos != -1 {
+			idx = strings.ToLower(title[:pos])
+		}
+		if idx == "" {
+			return
+		}
 
 		// func to clean sample input/output text
 		f := func(_ int, text *goquery.Selection) string {
-			str, _ := text.Html()
-			str = strings.ReplaceAll(str, "<br/>", "\n")
-			return strings.TrimSpace(str) + "\n"
+			return strings.TrimSpace(preText(text)) + "\n"
+		}
+		probs[idx] = ProblemPage{
+			Inputs:  stmt.Find(".sample-test .input pre").Map(f),
+			Outputs: stmt.Find(".sample-test .output pre").Map(f),
+			Data:    parseProblem(stmt),
+		}
+	})
+	return probs, nil
+}
+
+// minimum interval between fetch requests to a host
+const fetchInterval = 250 * time.Millisecond
+
+var (
+	// time after which next request to host is permitted
+	hostNext  = make(map[string]time.Time)
+	hostMutex sync.Mutex
+)
+
+// waitHost blocks till a (rate limited) request to host is permitted
+func waitHost(host string) {
+	hostMutex.Lock()
+	now := time.Now()
+	next := hostNext[host]
+	if next.Before(now) {
+		next = now
+	}
+	hostNext[host] = next.Add(fetchInterval)
+	hostMutex.Unlock()
+	time.Sleep(next.Sub(now))
+}
+
+var (
+	// matches allowed error of floats (like 'absolute or
+	// relative error doesn't exceed $$$10^{-6}$$$')
+	epsRegex = regexp.MustCompile(`(?i)(absolute or relative|relative or absolute|absolute|relative) error[^.]*?10\s*\^\s*\{?\s*-\s*(\d+)`)
+	// matches statements accepting any valid answer
+	anyRegex = regexp.MustCompile(`(?i)(print|output) any( of them| one of them)?\b|multiple (possible )?(answers|solutions)`)
+)
+
+// parseProblem extracts metadata of problem from its statement
+func parseProblem(stmt *goquery.Selection) ProblemData {
+	header := stmt.Find(".header")
+	// value of header property (without its title)
+	prop := func(class string) string {
+		sel := header.Find(class)
+		text := strings.TrimPrefix(sel.Text(), sel.Find(".property-title").Text())
+		return strings.TrimSpace(text)
+	}
+
+	prob := ProblemData{Input: prop(".input-file"), Output: prop(".output-file")}
+	prob.Statement, _ = goquery.OuterHtml(stmt)
+	// title is of the form 'A. Name'
+	prob.Name = strings.TrimSpace(header.Find(".title").Text())
+	if idx := strings.Index(prob.Name, ". "); idx != -1 {
+		prob.Name = prob.Name[idx+2:]
+	}
+	fmt.Sscanf(prop(".time-limit"), "%f", &prob.TimeLimit)
+	fmt.Sscanf(prop(".memory-limit"), "%d", &prob.MemoryLimit)
+
+	// interactive problems have interaction section
+	stmt.Find(".section-title").Each(func(_ int, sel *goquery.Selection) {
+		if strings.TrimSpace(sel.Text()) == "Interaction" {
+			prob.Interactive = true
 		}
-		// iterate over all input fields
-		splInp = append(splInp, prob.Find(".input pre").Map(f))
-		// iterate over all output fields
-		splOut = append(splOut, prob.Find(".output pre").Map(f))
 	})
-	return splInp, splOut, nil
+	text := stmt.Text()
+	if strings.Contains(strings.ToLower(text), "this is an interactive problem") {
+		prob.Interactive = true
+	}
+
+	// find checker hints from statement text
+	if match := epsRegex.FindStringSubmatch(text); match != nil {
+		exp, _ := strconv.Atoi(match[2])
+		eps := math.Pow10(-exp)
+		switch kind := strings.ToLower(match[1]); kind {
+		case "absolute":
+			prob.AbsEps = eps
+		case "relative":
+			prob.RelEps = eps
+		default:
+			prob.AbsEps, prob.RelEps = eps, eps
+		}
+	}
+	prob.AnyValid = anyRegex.MatchString(text)
+	return prob
 }
@@ -0,0 +1,58 @@
+package cln
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// ProblemFile holds fetched data of problem, in problem folder
+const ProblemFile = "problem.json"
+
+// ProblemData holds (fetched) metadata of a problem
+type ProblemData struct {
+	Name string `json:"name"`
+	// time limit (in seconds) and memory limit (in MB)
+	TimeLimit   float64 `json:"time_limit"`
+	MemoryLimit int     `json:"memory_limit"`
+	// input / output file ('standard input' / 'standard output' if none)
+	Input  string `json:"input"`
+	Output string `json:"output"`
+
+	Interactive bool `json:"interactive"`
+	// checker hints: allowed error of floats, and
+	// whether any of multiple valid answers is accepted
+	AbsEps   float64 `json:"abs_eps,omitempty"`
+	RelEps   float64 `json:"rel_eps,omitempty"`
+	AnyValid bool    `json:"any_valid,omitempty"`
+
+	// html of problem statement (saved separately)
+	Statement string `json:"-"`
+}
+
+// FileIO reports whether problem reads input / writes
+// output to files, instead of stdin / stdout
+func (p ProblemData) FileIO() bool {
+	return (p.Input != "" && p.Input != "standard input") ||
+		(p.Output != "" && p.Output != "standard output")
+}
+
+// LoadProblem reads problem data from problem.json in dir
+func LoadProblem(dir string) (ProblemData, error) {
+	prob := ProblemData{}
+	data, err := ioutil.ReadFile(filepath.Join(dir, ProblemFile))
+	if err != nil {
+		return prob, err
+	}
+	err = json.Unmarshal(data, &prob)
+	return prob, err
+}
+
+// SaveProblem writes problem data to problem.json in dir
+func SaveProblem(dir string, prob ProblemData) error {
+	data, err := json.MarshalIndent(prob, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ProblemFile), data, 0644)
+}
@@ -0,0 +1,52 @@
+package cln
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Sandbox holds resource limits of sandboxed scripts
+type Sandbox struct {
+	// cpu time (in seconds)
+	CPUTime uint64 `json:"cpu_time"`
+	// address space and file / output size (in MB)
+	Memory   uint64 `json:"memory"`
+	FileSize uint64 `json:"file_size"`
+	// number of processes (and threads). Scripts can't be
+	// run through shell, as the shell can't fork commands
+	Procs uint64 `json:"procs"`
+}
+
+var (
+	// ErrOutputLimit is returned if output of script exceeds limit
+	ErrOutputLimit = errors.New("Output limit exceeded")
+	// ErrSecurity is returned if sandboxed script writes
+	// files (left in its working directory)
+	ErrSecurity = errors.New("Security violation")
+)
+
+// name of environment variable holding sandbox limits
+const sandboxEnv = "CF_SANDBOX"
+
+// limitWriter writes data to buffer, and calls exceed (once)
+// if size of data exceeds limit (no limit if negative)
+type limitWriter struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+	exceed   func()
+}
+
+func (l *limitWriter) Write(p []byte) (int, error) {
+	if l.exceeded == false && l.limit >= 0 && l.buf.Len()+len(p) > l.limit {
+		// write data till limit (discard the rest)
+		l.buf.Write(p[:l.limit-l.buf.Len()])
+		l.exceeded = true
+		l.exceed()
+	}
+	if l.exceeded == true {
+		// discard data exceeding limit
+		return len(p), nil
+	}
+	return l.buf.Write(p)
+}
@@ -0,0 +1,87 @@
+package cln
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+)
+
+// RLIMIT_NPROC isn't defined in package syscall
+const rlimitNproc = 0x6
+
+// SandboxExec applies resource limits and replaces the current
+// process with the sandboxed script, if run as sandbox helper
+// (that is, the sandbox environment variable is set)
+func SandboxExec() {
+	val, ok := os.LookupEnv(sandboxEnv)
+	if ok == false {
+		return
+	}
+	os.Unsetenv(sandboxEnv)
+
+	var sb Sandbox
+	json.Unmarshal([]byte(val), &sb)
+	path, err := exec.LookPath(os.Args[1])
+	if err == nil {
+		limits := map[int]uint64{
+			syscall.RLIMIT_CPU:   sb.CPUTime,
+			syscall.RLIMIT_AS:    sb.Memory << 20,
+			syscall.RLIMIT_FSIZE: sb.FileSize << 20,
+			rlimitNproc:          sb.Procs,
+		}
+		for res, val := range limits {
+			syscall.Setrlimit(res, &syscall.Rlimit{Cur: val, Max: val})
+		}
+		err = syscall.Exec(path, os.Args[1:], os.Environ())
+	}
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(126)
+}
+
+// sandboxCmd modifies cmd to be run through the sandbox helper
+// (current executable) in (restricted) working directory dir
+func sandboxCmd(cmd *exec.Cmd, sb Sandbox, dir string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	// args referring to files in current directory are made
+	// absolute, as the working directory is changed
+	args := append([]string{cmd.Path}, cmd.Args[1:]...)
+	for i, arg := range args {
+		if _, err := os.Stat(arg); err == nil && filepath.IsAbs(arg) == false {
+			args[i], _ = filepath.Abs(arg)
+		}
+	}
+	data, _ := json.Marshal(sb)
+
+	cmd.Path = exe
+	cmd.Args = append([]string{exe}, args...)
+	cmd.Dir = dir
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, sandboxEnv+"="+string(data))
+	return nil
+}
+
+// sandboxErr maps signals of limit violations to errors. Processes
+// beyond the process limit can't be forked (fork fails with EAGAIN),
+// so such violations surface as runtime errors of the script
+func sandboxErr(err error) error {
+	exitErr, ok := err.(*exec.ExitError)
+	if ok == false {
+		return err
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if ok == false || status.Signaled() == false {
+		return err
+	}
+	if status.Signal() == syscall.SIGXFSZ {
+		return ErrOutputLimit
+	}
+	return err
+}
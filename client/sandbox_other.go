@@ -0,0 +1,22 @@
+//go:build !linux
+// +build !linux
+
+package cln
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// SandboxExec is a no-op, as sandbox is supported only on linux
+func SandboxExec() {}
+
+// sandboxCmd returns error, as sandbox is supported only on linux
+func sandboxCmd(cmd *exec.Cmd, sb Sandbox, dir string) error {
+	return fmt.Errorf("Sandbox is only supported on linux")
+}
+
+// sandboxErr returns err as is
+func sandboxErr(err error) error {
+	return err
+}
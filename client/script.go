@@ -0,0 +1,180 @@
+package cln
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// chars that must be quoted in script arguments
+const specialChars = " \t\n'\"\\$`&|;<>()*?[]#~=%!{}"
+
+// matches environment assignment (NAME=value)
+var envAssign = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// SplitScript parses script into arguments, following shell quoting
+// rules (single / double quotes and backslash escapes). Backslash is
+// not treated as an escape character on windows (path separator)
+func SplitScript(script string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	// isArg is set if current argument exists (it may be empty '')
+	isArg, quote := false, rune(0)
+	escape := runtime.GOOS != "windows"
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			// everything is literal till closing quote
+			if r == '\'' {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if escape && r == '\\' && i+1 < len(runes) &&
+				strings.ContainsRune("\"\\$`", runes[i+1]) {
+				i++
+				arg.WriteRune(runes[i])
+			} else {
+				arg.WriteRune(r)
+			}
+
+		case r == '\'' || r == '"':
+			quote, isArg = r, true
+
+		case escape && r == '\\' && i+1 < len(runes):
+			i++
+			arg.WriteRune(runes[i])
+			isArg = true
+
+		case r == ' ' || r == '\t' || r == '\n':
+			// end of current argument
+			if isArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				isArg = false
+			}
+
+		default:
+			arg.WriteRune(r)
+			isArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("Unterminated quote in script: %v", script)
+	}
+	if isArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// QuoteArg quotes (and escapes) arg, such that
+// it is parsed as a single argument of a script
+func QuoteArg(arg string) string {
+	if arg != "" && strings.ContainsAny(arg, specialChars) == false {
+		return arg
+	}
+	if runtime.GOOS == "windows" {
+		return `"` + arg + `"`
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// ReplaceArgs replaces each key of vals in script with its value.
+// Values outside quotes are quoted, and values within quotes are
+// escaped for the enclosing quote, to be parsed as (part of) a
+// single argument of the script
+func ReplaceArgs(script string, vals map[string]string) string {
+	var res strings.Builder
+	quote := byte(0)
+	escape := runtime.GOOS != "windows"
+
+	for i := 0; i < len(script); i++ {
+		key := ""
+		for k := range vals {
+			if strings.HasPrefix(script[i:], k) {
+				key = k
+				break
+			}
+		}
+		if key != "" {
+			res.WriteString(quoteIn(vals[key], quote))
+			i += len(key) - 1
+			continue
+		}
+
+		c := script[i]
+		res.WriteByte(c)
+		switch {
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == c:
+			quote = 0
+		case escape && quote != '\'' && c == '\\' && i+1 < len(script):
+			// escaped char is copied as is
+			i++
+			res.WriteByte(script[i])
+		}
+	}
+	return res.String()
+}
+
+// quoteIn quotes arg to be placed within quote (0 if not quoted)
+func quoteIn(arg string, quote byte) string {
+	switch {
+	case quote == 0:
+		return QuoteArg(arg)
+	case runtime.GOOS == "windows":
+		// quotes can't be escaped on windows
+		return arg
+	case quote == '\'':
+		return strings.ReplaceAll(arg, "'", `'\''`)
+	}
+	for _, c := range []string{"\\", `"`, "$", "`"} {
+		arg = strings.ReplaceAll(arg, c, "\\"+c)
+	}
+	return arg
+}
+
+// ShellScript returns script that runs script
+// through the system shell (sh -c / cmd /c)
+func ShellScript(script string) string {
+	if runtime.GOOS == "windows" {
+		return "cmd /c " + QuoteArg(script)
+	}
+	return "sh -c " + QuoteArg(script)
+}
+
+// Command parses script and returns cmd to run it. Leading
+// environment assignments (NAME=value) are set in its environment
+func Command(ctx context.Context, script string, args ...string) (*exec.Cmd, error) {
+	cmds, err := SplitScript(script)
+	if err != nil {
+		return nil, err
+	}
+	// separate environment assignments from the script
+	var env []string
+	for len(cmds) != 0 && envAssign.MatchString(cmds[0]) {
+		env, cmds = append(env, cmds[0]), cmds[1:]
+	}
+	if len(cmds) == 0 {
+		return nil, fmt.Errorf("Empty script")
+	}
+
+	cmd := exec.CommandContext(ctx, cmds[0], append(cmds[1:], args...)...)
+	if len(env) != 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd, nil
+}
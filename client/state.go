@@ -0,0 +1,34 @@
+package cln
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// StateFile holds results of the last test run, in problem folder
+const StateFile = ".cf-state.json"
+
+// State of last test run in current directory
+type State struct {
+	Failed []string `json:"failed"`
+}
+
+// LoadState reads state of last test run from current directory
+func LoadState() (State, error) {
+	state := State{}
+	data, err := ioutil.ReadFile(StateFile)
+	if err != nil {
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// SaveState writes state of test run to current directory
+func SaveState(state State) error {
+	data, err := json.MarshalIndent(state, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(StateFile, data, 0644)
+}
@@ -0,0 +1,356 @@
+package cln
+
+import (
+	cfg "cf/config"
+	pkg "cf/packages"
+
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/fatih/color"
+)
+
+// html page of statement. TeX is pre-rendered as readable text,
+// and typeset with MathJax (as codeforces) if it can be loaded
+const statementHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%v</title>
+<style>
+body { max-width: 50em; margin: auto; padding: 1em; font-family: serif; line-height: 1.5; }
+.header { text-align: center; }
+.header .title { font-size: 1.5em; font-weight: bold; }
+.property-title { display: inline; margin-right: 0.5em; }
+.section-title { font-size: 1.2em; font-weight: bold; margin-top: 1em; }
+.sample-test .title { font-weight: bold; }
+pre { background: #f5f5f5; border: 1px solid #ddd; padding: 0.5em; }
+.tex-font-style-bf { font-weight: bold; }
+.tex-font-style-it { font-style: italic; }
+.tex-font-style-tt { font-family: monospace; }
+img { max-width: 100%%; }
+.tex { font-style: italic; }
+</style>
+<script type="text/x-mathjax-config">
+MathJax.Hub.Config({ tex2jax: {
+	inlineMath: [['$$$', '$$$']],
+	displayMath: [['$$$$$$', '$$$$$$']]
+}});
+</script>
+<script src="https://cdnjs.cloudflare.com/ajax/libs/mathjax/2.7.9/MathJax.js?config=TeX-AMS_HTML"></script>
+</head>
+<body>
+%v
+<script>
+if (window.MathJax) {
+	// restore TeX of pre-rendered text, to be typeset
+	document.querySelectorAll('.tex').forEach(function (tex) {
+		tex.textContent = tex.getAttribute('data-tex');
+	});
+	MathJax.Hub.Queue(['Typeset', MathJax.Hub]);
+}
+</script>
+</body>
+</html>
+`
+
+// SaveStatement saves statement of problem to statement.md and
+// statement.html (images embedded, TeX readable offline) in dir.
+// Images are saved to dir/images, referred to by statement.md
+func SaveStatement(dir string, prob ProblemData, link url.URL) error {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(prob.Statement))
+	if err != nil {
+		return err
+	}
+	stmt := doc.Find(".problem-statement").First()
+	stmt.Find("script").Remove()
+
+	// download images, to view statement offline
+	c := cfg.Session.Client
+	uris := make(map[string]string)
+	stmt.Find("img").Each(func(i int, img *goquery.Selection) {
+		src, _ := img.Attr("src")
+		ref, err := url.Parse(src)
+		if err != nil {
+			return
+		}
+		src = link.ResolveReference(ref).String()
+		img.SetAttr("src", src)
+		body, err := pkg.GetReqBody(&c, src)
+		if err != nil || len(body) == 0 {
+			// refer to image online
+			return
+		}
+		ext := path.Ext(ref.Path)
+		name := path.Join("images", strconv.Itoa(i)+ext)
+		os.MkdirAll(filepath.Join(dir, "images"), os.ModePerm)
+		if err := ioutil.WriteFile(filepath.Join(dir, name), body, 0644); err != nil {
+			return
+		}
+		img.SetAttr("src", name)
+		uris[name] = "data:" + mime.TypeByExtension(ext) + ";base64," +
+			base64.StdEncoding.EncodeToString(body)
+	})
+
+	md := markdown(stmt)
+	if err := ioutil.WriteFile(filepath.Join(dir, "statement.md"), []byte(md), 0644); err != nil {
+		return err
+	}
+
+	// embed images in html
+	stmt.Find("img").Each(func(_ int, img *goquery.Selection) {
+		if uri, ok := uris[img.AttrOr("src", "")]; ok {
+			img.SetAttr("src", uri)
+		}
+	})
+	body, err := goquery.OuterHtml(stmt)
+	if err != nil {
+		return err
+	}
+	// pre-render TeX, keeping its source for MathJax
+	body = htmlTeXRegex.ReplaceAllStringFunc(body, func(tex string) string {
+		text := strings.Trim(tex, "$")
+		return `<span class="tex" data-tex="` + tex + `">` + readableTeX(text) + `</span>`
+	})
+	page := fmt.Sprintf(statementHTML, html.EscapeString(prob.Name), body)
+	return ioutil.WriteFile(filepath.Join(dir, "statement.html"), []byte(page), 0644)
+}
+
+var (
+	// matches runs of whitespace (in html text)
+	spaceRegex = regexp.MustCompile(`\s+`)
+	// matches 3 or more consecutive newlines
+	blankRegex = regexp.MustCompile(`\n{3,}`)
+	// matches TeX (of the form $$$..$$$ and $$$$$$..$$$$$$) in html
+	htmlTeXRegex = regexp.MustCompile(`(?s)\$\$\$\$\$\$.+?\$\$\$\$\$\$|\$\$\$.+?\$\$\$`)
+)
+
+// markdown converts html of statement in sel to markdown.
+// TeX is preserved, with $$$ delimiters replaced by $
+func markdown(sel *goquery.Selection) string {
+	var b strings.Builder
+	writeMarkdown(&b, sel)
+
+	// trim spaces around lines (except in code blocks)
+	lines := strings.Split(b.String(), "\n")
+	for i, fence := 0, false; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "```") {
+			fence = !fence
+		} else if fence == false {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+	}
+	md := strings.Join(lines, "\n")
+	md = strings.ReplaceAll(md, "$$$$$$", "$$")
+	md = strings.ReplaceAll(md, "$$$", "$")
+	md = blankRegex.ReplaceAllString(md, "\n\n")
+	return strings.TrimSpace(md) + "\n"
+}
+
+// writeMarkdown writes markdown of contents of sel to b
+func writeMarkdown(b *strings.Builder, sel *goquery.Selection) {
+	sel.Contents().Each(func(_ int, s *goquery.Selection) {
+		name := goquery.NodeName(s)
+		if name == "#text" {
+			b.WriteString(spaceRegex.ReplaceAllString(s.Text(), " "))
+			return
+		} else if strings.HasPrefix(name, "#") {
+			// comments, etc
+			return
+		}
+		// markdown of contents of element
+		inner := func() string {
+			var in strings.Builder
+			writeMarkdown(&in, s)
+			return strings.TrimSpace(in.String())
+		}
+
+		switch {
+		case s.HasClass("header"):
+			fmt.Fprintf(b, "# %v\n\n", strings.TrimSpace(s.Find(".title").Text()))
+			for _, class := range []string{".time-limit", ".memory-limit", ".input-file", ".output-file"} {
+				prop := s.Find(class)
+				title := prop.Find(".property-title").Text()
+				value := strings.TrimPrefix(prop.Text(), title)
+				if title != "" {
+					fmt.Fprintf(b, "- %v: %v\n", title, strings.TrimSpace(value))
+				}
+			}
+			b.WriteString("\n")
+
+		case s.HasClass("section-title"):
+			fmt.Fprintf(b, "\n\n## %v\n\n", strings.TrimSpace(s.Text()))
+
+		case s.HasClass("title"):
+			// title of sample input / output
+			fmt.Fprintf(b, "\n\n### %v\n\n", strings.TrimSpace(s.Text()))
+
+		case s.HasClass("tex-font-style-bf"):
+			fmt.Fprintf(b, "**%v**", inner())
+		case s.HasClass("tex-font-style-it"), s.HasClass("tex-font-style-sl"):
+			fmt.Fprintf(b, "*%v*", inner())
+		case s.HasClass("tex-font-style-tt"):
+			fmt.Fprintf(b, "`%v`", strings.TrimSpace(s.Text()))
+
+		default:
+			switch name {
+			case "p", "div", "center":
+				fmt.Fprintf(b, "\n\n%v\n\n", inner())
+			case "br":
+				b.WriteString("\n")
+			case "b", "strong":
+				fmt.Fprintf(b, "**%v**", inner())
+			case "i", "em":
+				fmt.Fprintf(b, "*%v*", inner())
+			case "tt", "code":
+				fmt.Fprintf(b, "`%v`", strings.TrimSpace(s.Text()))
+			case "a":
+				fmt.Fprintf(b, "[%v](%v)", inner(), s.AttrOr("href", ""))
+			case "img":
+				fmt.Fprintf(b, "![](%v)", s.AttrOr("src", ""))
+			case "pre":
+				fmt.Fprintf(b, "\n\n```\n%v\n```\n\n", strings.TrimSpace(preText(s)))
+			case "ul", "ol":
+				b.WriteString("\n\n")
+				s.ChildrenFiltered("li").Each(func(i int, li *goquery.Selection) {
+					var item strings.Builder
+					writeMarkdown(&item, li)
+					text := spaceRegex.ReplaceAllString(item.String(), " ")
+					if name == "ol" {
+						fmt.Fprintf(b, "%d. %v\n", i+1, strings.TrimSpace(text))
+					} else {
+						fmt.Fprintf(b, "- %v\n", strings.TrimSpace(text))
+					}
+				})
+				b.WriteString("\n")
+			case "table":
+				b.WriteString("\n\n")
+				s.Find("tr").Each(func(i int, tr *goquery.Selection) {
+					var cells []string
+					tr.Find("th, td").Each(func(_ int, td *goquery.Selection) {
+						var cell strings.Builder
+						writeMarkdown(&cell, td)
+						text := spaceRegex.ReplaceAllString(cell.String(), " ")
+						cells = append(cells, strings.TrimSpace(text))
+					})
+					fmt.Fprintf(b, "| %v |\n", strings.Join(cells, " | "))
+					if i == 0 {
+						fmt.Fprintf(b, "|%v\n", strings.Repeat(" --- |", len(cells)))
+					}
+				})
+				b.WriteString("\n")
+			case "style":
+			default:
+				writeMarkdown(b, s)
+			}
+		}
+	})
+}
+
+// preText returns text of pre block, with lines separated by
+// <br> tags or (in newer statements) as div elements
+func preText(sel *goquery.Selection) string {
+	if lines := sel.Find("div"); lines.Length() != 0 {
+		return strings.Join(lines.Map(func(_ int, s *goquery.Selection) string {
+			return s.Text()
+		}), "\n")
+	}
+	str, _ := sel.Html()
+	str = strings.ReplaceAll(str, "<br/>", "\n")
+	doc, _ := goquery.NewDocumentFromReader(strings.NewReader("<pre>" + str + "</pre>"))
+	return doc.Find("pre").Text()
+}
+
+var (
+	// regex and format of inline markdown in terminal
+	inlineRegex = []struct {
+		regex  *regexp.Regexp
+		format func(string) string
+	}{
+		{regexp.MustCompile("`([^`]+)`"), style(color.FgCyan)},
+		{regexp.MustCompile(`\$\$?([^$]+)\$\$?`), func(tex string) string {
+			return style(color.Italic)(readableTeX(tex))
+		}},
+		{regexp.MustCompile(`!\[\]\(([^)]+)\)`), func(src string) string {
+			return "[image: " + src + "]"
+		}},
+		{regexp.MustCompile(`\*\*([^*]+)\*\*`), style(color.Bold)},
+		{regexp.MustCompile(`\*([^*]+)\*`), style(color.Italic)},
+	}
+	// matches TeX commands
+	texRegex = regexp.MustCompile(`\\([a-zA-Z]+|[,;! {}%$&_])`)
+	// symbols of common TeX commands
+	texSymbols = map[string]string{
+		"le": "≤", "leq": "≤", "ge": "≥", "geq": "≥", "ne": "≠", "neq": "≠",
+		"cdot": "·", "times": "×", "ldots": "…", "dots": "…", "cdots": "⋯",
+		"to": "→", "infty": "∞", "pm": "±", "oplus": "⊕", "in": "∈",
+		"lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+		"sum": "Σ", "prod": "Π", "sqrt": "√", "approx": "≈", "equiv": "≡",
+		"left": "", "right": "", "mathrm": "", "text": "", "bmod": "mod", "mod": "mod",
+		",": " ", ";": " ", "!": "", " ": " ",
+		"{": "{", "}": "}", "%": "%", "$": "$", "&": "&", "_": "_",
+	}
+)
+
+// style returns func formatting text with attrs
+func style(attrs ...color.Attribute) func(string) string {
+	return func(text string) string {
+		return color.New(attrs...).Sprint(text)
+	}
+}
+
+// readableTeX replaces common TeX commands with their symbols
+func readableTeX(tex string) string {
+	return texRegex.ReplaceAllStringFunc(tex, func(cmd string) string {
+		if sym, ok := texSymbols[cmd[1:]]; ok {
+			return sym
+		}
+		return cmd
+	})
+}
+
+// RenderMarkdown formats markdown (of statement) for terminal
+func RenderMarkdown(md string) string {
+	var b strings.Builder
+	fence := false
+	for _, line := range strings.Split(md, "\n") {
+		if strings.HasPrefix(line, "```") {
+			fence = !fence
+			continue
+		} else if fence == true {
+			// indent code blocks (sample tests)
+			b.WriteString("    " + line + "\n")
+			continue
+		}
+
+		for _, in := range inlineRegex {
+			format := in.format
+			line = in.regex.ReplaceAllStringFunc(line, func(match string) string {
+				return format(in.regex.FindStringSubmatch(match)[1])
+			})
+		}
+		switch {
+		case strings.HasPrefix(line, "# "):
+			line = color.New(color.FgCyan, color.Bold).Sprint(line[2:])
+		case strings.HasPrefix(line, "## "):
+			line = color.New(color.FgYellow, color.Bold).Sprint(line[3:])
+		case strings.HasPrefix(line, "### "):
+			line = color.New(color.Bold).Sprint(line[4:])
+		case strings.HasPrefix(line, "- "):
+			line = "  • " + line[2:]
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
@@ -9,16 +9,43 @@ import (
 	"io/ioutil"
 	"net/url"
 	"path"
+	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
 // Submit uploads form data and submits user code
 func Submit(contest, problem, langID, file string, link url.URL) error {
+	link.Path = path.Join(link.Path, "submit")
+	return submit(contest, langID, file, link, url.Values{
+		"submittedProblemIndex": {problem},
+		"contestId":             {contest},
+	})
+}
+
+// SubmitProblemset submits user code through the problemset
+// submit form. link points to the host
+func SubmitProblemset(contest, problem, langID, file string, link url.URL) error {
+	link.Path = path.Join(link.Path, "problemset", "submit")
+	return submit(contest, langID, file, link, url.Values{
+		"submittedProblemCode": {contest + strings.ToUpper(problem)},
+	})
+}
+
+// SubmitAcmsguru submits user code through the acmsguru
+// submit form. link points to the acmsguru problemset
+func SubmitAcmsguru(contest, problem, langID, file string, link url.URL) error {
+	link.Path = path.Join(link.Path, "submit")
+	return submit(contest, langID, file, link, url.Values{
+		"submittedProblemCode": {problem},
+	})
+}
+
+// submit posts source file (with problem fields) to submit form at link
+func submit(contest, langID, file string, link url.URL, fields url.Values) error {
 	// form redirection prevention is removed while submitting
 	c := cfg.Session.Client
 	c.CheckRedirect = pkg.RedirectCheck
-	link.Path = path.Join(link.Path, "submit")
 	body, err := pkg.GetReqBody(&c, link.String())
 	if err != nil {
 		return err
@@ -36,19 +63,21 @@ func Submit(contest, problem, langID, file string, link url.URL) error {
 	bfaa := "883b704dbe5c70e1e61de4d8aff2da32"
 	// post form data (remove redirection prevention)
 	c.CheckRedirect = nil
-	body, err = pkg.PostReqBody(&c, link.String(), url.Values{
-		"csrf_token":            {csrf},
-		"ftaa":                  {ftaa},
-		"bfaa":                  {bfaa},
-		"action":                {"submitSolutionFormSubmitted"},
-		"submittedProblemIndex": {problem},
-		"programTypeId":         {langID},
-		"contestId":             {contest},
-		"source":                {string(data)},
-		"tabSize":               {"4"},
-		"_tta":                  {"176"},
-		"sourceCodeConfirmed":   {"true"},
-	})
+	form := url.Values{
+		"csrf_token":          {csrf},
+		"ftaa":                {ftaa},
+		"bfaa":                {bfaa},
+		"action":              {"submitSolutionFormSubmitted"},
+		"programTypeId":       {langID},
+		"source":              {string(data)},
+		"tabSize":             {"4"},
+		"_tta":                {"176"},
+		"sourceCodeConfirmed": {"true"},
+	}
+	for key, val := range fields {
+		form[key] = val
+	}
+	body, err = pkg.PostReqBody(&c, link.String(), form)
 	if err != nil {
 		return err
 	}
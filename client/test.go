@@ -2,62 +2,183 @@ package cln
 
 import (
 	cfg "cf/config"
-	pkg "cf/packages"
 
 	"bytes"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"math/big"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
-	"github.com/gosuri/uitable"
 )
 
-// FindTests finds all returns all sample input/output
-// files present in the current directory
-func FindTests() ([]string, []string, error) {
-	// function to read and return data of
-	// all files matching pattern in dir
-	readTests := func(pattern string) ([]string, error) {
-		var data []string
-		glob, _ := filepath.Glob(pattern)
+// matches integer tokens (compared exactly)
+var isInteger = regexp.MustCompile(`^[+-]?[0-9]+$`)
+
+// max size of captured stderr of a script (64 KB)
+const maxStderr = 64 << 10
+
+type (
+	// Usage holds resources used by an executed script
+	Usage struct {
+		Elapsed, CPUTime time.Duration
+		// peak resident memory (in KB)
+		Memory int64
+	}
+	// Test holds data of a test case. AnsFile
+	// is empty if test has no expected output
+	Test struct {
+		Name, Input, Answer,
+		InpFile, AnsFile string
+	}
+	// Interaction holds result of running
+	// solution against interactor on a test
+	Interaction struct {
+		Usage Usage
+		Verdict, Message, Output,
+		Transcript, Stderr string
+	}
+)
+
+// String returns cpu time (judged against time limit),
+// wall clock time taken and memory used
+func (u Usage) String() string {
+	return fmt.Sprintf("%v (%v wall), %.1f MB", u.CPUTime.String(),
+		u.Elapsed.String(), float64(u.Memory)/1024)
+}
+
+// usageOf returns resources used by the exited cmd
+func usageOf(cmd *exec.Cmd, elapsed time.Duration) Usage {
+	u := Usage{Elapsed: elapsed.Truncate(time.Millisecond)}
+	if state := cmd.ProcessState; state != nil {
+		cpu := state.UserTime() + state.SystemTime()
+		u.CPUTime = cpu.Truncate(time.Millisecond)
+		u.Memory = peakMemory(state)
+	}
+	return u
+}
+
+// FindTests finds all test input files (and their answer files)
+// in the current directory, matching configured test patterns.
+// Tests are sorted by name, with numeric names in numeric order
+func FindTests() ([]Test, error) {
+	var tests []Test
+	// index of test with given input file
+	isFound := make(map[string]int)
+	for _, pattern := range cfg.Settings.TestPatterns {
+		// glob returns paths with os specific separators
+		prefix, suffix := splitPattern(filepath.FromSlash(pattern.Input))
+		glob, _ := filepath.Glob(pattern.Input)
 		for _, file := range glob {
-			str, err := ioutil.ReadFile(file)
-			if err != nil {
-				return nil, err
+			if info, err := os.Stat(file); err != nil || info.IsDir() {
+				continue
+			}
+			// extract test name (matched by '*') from file name
+			name := strings.TrimSuffix(strings.TrimPrefix(file, prefix), suffix)
+			ansFile := strings.Replace(filepath.FromSlash(pattern.Output), "*", name, 1)
+			if _, err := os.Stat(ansFile); err != nil {
+				ansFile = ""
+			}
+
+			if idx, ok := isFound[file]; ok {
+				// set answer file, if not found by previous patterns
+				if tests[idx].AnsFile == "" {
+					tests[idx].AnsFile = ansFile
+				}
+				continue
 			}
-			data = append(data, string(str))
+			isFound[file] = len(tests)
+			tests = append(tests, Test{Name: name, InpFile: file, AnsFile: ansFile})
 		}
-		return data, nil
-	}
-	// fetch test cases
-	inp, err := readTests("[0-9].in")
-	if err != nil {
-		return nil, nil, err
 	}
-	out, err := readTests("[0-9].out")
-	if err != nil {
-		return nil, nil, err
-	}
-	// validate input output data
-	// check for i/o count equality
-	// and existence of non-zero test files
-	if len(inp) != len(out) {
-		err := fmt.Errorf("Unequal number of input/output test files")
-		return nil, nil, err
-	} else if len(inp) == 0 {
+	if len(tests) == 0 {
 		err := fmt.Errorf("No test files found")
-		return nil, nil, err
+		return nil, err
+	}
+
+	// read data of input / answer files
+	for i := range tests {
+		data, err := ioutil.ReadFile(tests[i].InpFile)
+		if err != nil {
+			return nil, err
+		}
+		tests[i].Input = string(data)
+		if tests[i].AnsFile == "" {
+			continue
+		}
+		data, err = ioutil.ReadFile(tests[i].AnsFile)
+		if err != nil {
+			return nil, err
+		}
+		tests[i].Answer = string(data)
+	}
+
+	// sort tests by name (numeric names first)
+	sort.SliceStable(tests, func(i, j int) bool {
+		a, errA := strconv.Atoi(tests[i].Name)
+		b, errB := strconv.Atoi(tests[j].Name)
+		switch {
+		case errA == nil && errB == nil:
+			return a < b
+		case errA == nil || errB == nil:
+			return errA == nil
+		default:
+			return tests[i].Name < tests[j].Name
+		}
+	})
+	return tests, nil
+}
+
+// TestFiles returns path of input / answer files of
+// test name in dir, based on first configured pattern
+func TestFiles(dir, name string) (string, string) {
+	pattern := cfg.TestPattern{Input: "*.in", Output: "*.out"}
+	if len(cfg.Settings.TestPatterns) != 0 {
+		pattern = cfg.Settings.TestPatterns[0]
+	}
+	inp := strings.Replace(pattern.Input, "*", name, 1)
+	out := strings.Replace(pattern.Output, "*", name, 1)
+	return filepath.Join(dir, inp), filepath.Join(dir, out)
+}
+
+// SaveTest saves input/output data as test files with
+// the next free index in the current directory (answer
+// file is omitted if out is empty). Returns the test name
+func SaveTest(inp, out string) (string, error) {
+	for idx := 0; ; idx++ {
+		name := strconv.Itoa(idx)
+		inpFile, outFile := TestFiles("", name)
+		// check if test already exists
+		if _, err := os.Stat(inpFile); os.IsNotExist(err) {
+			os.MkdirAll(filepath.Dir(inpFile), os.ModePerm)
+			os.MkdirAll(filepath.Dir(outFile), os.ModePerm)
+			if err := ioutil.WriteFile(inpFile, []byte(inp), 0644); err != nil {
+				return "", err
+			} else if out == "" {
+				return name, nil
+			}
+			return name, ioutil.WriteFile(outFile, []byte(out), 0644)
+		}
 	}
-	return inp, out, nil
+}
+
+// splitPattern returns parts of pattern before and after '*'
+func splitPattern(pattern string) (string, string) {
+	idx := strings.Index(pattern, "*")
+	if idx == -1 {
+		return pattern, ""
+	}
+	return pattern[:idx], pattern[idx+1:]
 }
 
 // FindSourceFiles finds all code files in current dir
@@ -96,32 +217,297 @@ func FindTmpltsConfig(file string) []cfg.Template {
 }
 
 // ExecScript runs script with input and timeout and returns the
-// time taken, stdout, stderr. Returns deadlineExceeded if timout occurs
-func ExecScript(script, input string, dur int) (time.Duration, string, error) {
-	cmds := strings.Split(script, " ")
-	var stdout bytes.Buffer
-
-	// set timer of `dur` seconds for execution of script
-	secs := time.Duration(dur) * time.Second
-	ctx, cancel := context.WithTimeout(context.Background(), secs)
+// resources used, stdout and stderr (capped to maxStderr bytes).
+// Script is killed if it runs longer than dur. Script is run in a
+// restricted working directory, with limits of sb (if non-nil)
+func ExecScript(script, input string, dur time.Duration, sb *Sandbox) (Usage, string, string, error) {
+	// set timer of dur for execution of script
+	ctx, cancel := context.WithTimeout(context.Background(), dur)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, cmds[0], cmds[1:]...)
+	cmd, err := Command(ctx, script)
+	if err != nil {
+		return Usage{}, "", "", err
+	}
+	// output exceeding limit terminates script
+	stdout := &limitWriter{limit: -1}
+	if sb != nil {
+		stdout.limit = int(sb.FileSize << 20)
+		stdout.exceed = cancel
+
+		dir, err := ioutil.TempDir("", "cf-sandbox")
+		if err != nil {
+			return Usage{}, "", "", err
+		}
+		defer os.RemoveAll(dir)
+		if err := sandboxCmd(cmd, *sb, dir); err != nil {
+			return Usage{}, "", "", err
+		}
+	}
 	cmd.Stdin = strings.NewReader(input)
-	cmd.Stdout = io.Writer(&stdout)
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = stdout
+	// debug output beyond cap is discarded
+	stderr := &limitWriter{limit: maxStderr, exceed: func() {}}
+	cmd.Stderr = stderr
+
+	// run script and measure resources used
+	start := time.Now()
+	err = cmd.Run()
+	usage := usageOf(cmd, time.Since(start))
+
+	if sb != nil {
+		err = sandboxErr(err)
+		// writing files isn't permitted in sandbox
+		if files, _ := ioutil.ReadDir(cmd.Dir); err == nil && len(files) != 0 {
+			err = ErrSecurity
+		}
+		if stdout.exceeded == true {
+			err = ErrOutputLimit
+		}
+	}
+	return usage, stdout.buf.String(), capped(stderr), err
+}
+
+// capped returns data written to l, noting if it was truncated
+func capped(l *limitWriter) string {
+	if l.exceeded == true {
+		return l.buf.String() + "\n... (truncated)\n"
+	}
+	return l.buf.String()
+}
+
+// ExecCompile runs (compilation) script and returns
+// the combined stdout / stderr output of the script
+func ExecCompile(script string) (string, error) {
+	cmd, err := Command(context.Background(), script)
+	if err != nil {
+		return "", err
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// FindProgram finds executable file `name` (checker, etc)
+// in the current directory and returns script to run it.
+// Returns empty string if no such file exists
+func FindProgram(name string) string {
+	for _, file := range []string{name, name + ".exe"} {
+		if info, err := os.Stat(file); err == nil && info.IsDir() == false {
+			return "." + string(os.PathSeparator) + file
+		}
+	}
+	return ""
+}
+
+// ExecChecker runs testlib style checker script with files holding
+// input, output and answer data. Returns verdict (based on exit code)
+// and checker message. Exit codes: 0 = AC, 1 = WA, 2 = PE, 3 = FAIL
+func ExecChecker(script, inp, out, ans string) (string, string, error) {
+	// write data to temporary files, passed as args to checker
+	dir, err := ioutil.TempDir("", "cf-checker")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.RemoveAll(dir)
+
+	var args []string
+	for i, data := range []string{inp, out, ans} {
+		file := filepath.Join(dir, strconv.Itoa(i)+".txt")
+		if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+			return "", "", err
+		}
+		args = append(args, file)
+	}
+
+	// set timer of 10 seconds for execution of checker
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd, err := Command(ctx, script, args...)
+	if err != nil {
+		return "", "", err
+	}
+	// testlib writes checker message to stderr
+	msg, err := cmd.CombinedOutput()
+
+	verdict, err := testlibVerdict("Checker", err)
+	return verdict, strings.TrimSpace(string(msg)), err
+}
+
+// ExecValidator runs testlib style validator script with input
+// data passed to stdin. Returns whether input is valid (exit
+// code 0) and the validator message (if invalid)
+func ExecValidator(script, inp string) (bool, string, error) {
+	// set timer of 10 seconds for execution of validator
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cmd, err := Command(ctx, script)
+	if err != nil {
+		return false, "", err
+	}
+	cmd.Stdin = strings.NewReader(inp)
+	// testlib writes validator message to stderr
+	msg, err := cmd.CombinedOutput()
+	if _, ok := err.(*exec.ExitError); ok && ctx.Err() == nil {
+		return false, strings.TrimSpace(string(msg)), nil
+	} else if ctx.Err() != nil {
+		return false, "", fmt.Errorf("Validator timed out")
+	}
+	return err == nil, "", err
+}
+
+// ExecInteractor runs script with its stdin / stdout cross piped to
+// the testlib style interactor script, with input and answer data
+// passed as files. The verdict is decided by exit code of interactor
+func ExecInteractor(script, interactor, inp, ans string, dur time.Duration) (Interaction, error) {
+	var res Interaction
+	// write data to temporary files, passed as args to interactor
+	dir, err := ioutil.TempDir("", "cf-interactor")
+	if err != nil {
+		return res, err
+	}
+	defer os.RemoveAll(dir)
+
+	inpFile := filepath.Join(dir, "0.txt")
+	outFile := filepath.Join(dir, "1.txt")
+	ansFile := filepath.Join(dir, "2.txt")
+	ioutil.WriteFile(inpFile, []byte(inp), 0644)
+	ioutil.WriteFile(ansFile, []byte(ans), 0644)
+
+	// set timer of dur for execution of both scripts
+	ctx, cancel := context.WithTimeout(context.Background(), dur)
+	defer cancel()
+
+	sol, err := Command(ctx, script)
+	if err != nil {
+		return res, err
+	}
+	itr, err := Command(ctx, interactor, inpFile, outFile, ansFile)
+	if err != nil {
+		return res, err
+	}
 
-	// run script and measure time taken
+	// cross pipe stdin / stdout, logging exchanged data
+	var transcript strings.Builder
+	var mutex sync.Mutex
+	solIn, _ := sol.StdinPipe()
+	itrIn, _ := itr.StdinPipe()
+	solOut := &logWriter{w: itrIn, prefix: "> ", log: &transcript, mutex: &mutex}
+	itrOut := &logWriter{w: solIn, prefix: "< ", log: &transcript, mutex: &mutex}
+	sol.Stdout, itr.Stdout = solOut, itrOut
+	// testlib writes interactor message to stderr
+	var msg bytes.Buffer
+	stderr := &limitWriter{limit: maxStderr, exceed: func() {}}
+	sol.Stderr, itr.Stderr = stderr, &msg
+
+	if err := itr.Start(); err != nil {
+		return res, err
+	}
 	start := time.Now()
-	err := cmd.Run()
-	since := time.Since(start).Truncate(time.Millisecond)
+	if err := sol.Start(); err != nil {
+		itr.Process.Kill()
+		itr.Wait()
+		return res, err
+	}
+
+	// close pipe to interactor once solution exits
+	solDone := make(chan error, 1)
+	go func() {
+		err := sol.Wait()
+		res.Usage = usageOf(sol, time.Since(start))
+		itrIn.Close()
+		solDone <- err
+	}()
+	itrErr := itr.Wait()
+	// solution can't receive any further data
+	solIn.Close()
+	solErr := <-solDone
+
+	solOut.Flush()
+	itrOut.Flush()
+	res.Transcript = transcript.String()
+	res.Message = strings.TrimSpace(msg.String())
+	res.Stderr = capped(stderr)
+	output, _ := ioutil.ReadFile(outFile)
+	res.Output = string(output)
+
+	switch {
+	case ctx.Err() != nil:
+		res.Verdict = "TLE"
+	case itrErr != nil:
+		res.Verdict, err = testlibVerdict("Interactor", itrErr)
+	case solErr != nil:
+		res.Verdict, res.Message = "RTE", ExitReason(solErr)
+	default:
+		res.Verdict = "AC"
+	}
+	return res, err
+}
+
+// testlibVerdict determines verdict from exit status
+// (err) of testlib style program (checker / interactor)
+func testlibVerdict(name string, err error) (string, error) {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		switch exitErr.ExitCode() {
+		case 1:
+			return "WA", nil
+		case 2:
+			return "PE", nil
+		case 3:
+			return "FAIL", nil
+		default:
+			return "", fmt.Errorf("%v exited with code %d", name, exitErr.ExitCode())
+		}
+	} else if err != nil {
+		return "", err
+	}
+	return "AC", nil
+}
+
+// logWriter writes data to w, while logging each
+// line written (with prefix) to a shared log
+type logWriter struct {
+	w       io.Writer
+	prefix  string
+	pending []byte
+	log     *strings.Builder
+	mutex   *sync.Mutex
+}
+
+func (l *logWriter) Write(p []byte) (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	// log complete lines, buffer the remaining
+	l.pending = append(l.pending, p...)
+	for {
+		i := bytes.IndexByte(l.pending, '\n')
+		if i == -1 {
+			break
+		}
+		l.log.WriteString(l.prefix + string(l.pending[:i+1]))
+		l.pending = l.pending[i+1:]
+	}
+	// errors are ignored, as the reading end
+	// may exit before all data is written
+	l.w.Write(p)
+	return len(p), nil
+}
 
-	return since, stdout.String(), err
+// Flush logs data of incomplete line (if any)
+func (l *logWriter) Flush() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if len(l.pending) != 0 {
+		l.log.WriteString(l.prefix + string(l.pending) + "\n")
+		l.pending = nil
+	}
 }
 
 // Validator modifies and returns output / expected output
-// based on flags passed (ignore-case / ignore-exp)
-func Validator(out, ans string, igCase bool, exp int) (string, string) {
+// based on flags passed (ignore-case). Leading / trailing
+// spaces of each line are removed
+func Validator(out, ans string, igCase bool) (string, string) {
 	// cleans the data based on validator flags
 	f := func(data string) string {
 		// remove trailing and leading spaces
@@ -130,65 +516,55 @@ func Validator(out, ans string, igCase bool, exp int) (string, string) {
 		if igCase == true {
 			data = strings.ToLower(data)
 		}
-		outData := ""
-		// omit exp difference < 1e-<exp>
-		for _, line := range strings.Split(data, "\n") {
-			outLine := ""
-			for _, wrd := range strings.Split(line, " ") {
-				fVal, err := strconv.ParseFloat(wrd, 64)
-				// valid float number, modify accordingly
-				if err == nil {
-					// truncate till exp places after point
-					wrd = big.NewFloat(fVal).Text('f', exp)
-					wrd = strings.TrimRight(strings.TrimRight(wrd, "0"), ".")
-				}
-				// join words with space in between
-				outLine += wrd + " "
-			}
-			// remove trailing space
-			outLine = strings.TrimSpace(outLine)
-			// join lines with newline in between
-			outData += outLine + "\n"
+		lines := strings.Split(data, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimSpace(line)
 		}
-		// remove trailing newline
-		outData = strings.TrimSpace(outData)
-		return outData
+		return strings.Join(lines, "\n")
 	}
 	// return formatted strings
 	return f(out), f(ans)
 }
 
-// PrintDiff is run if outputs don't match
-// returns input data, and then the diff of => out vs ans
-func PrintDiff(inp, out, ans string) string {
-	// variable to hold diff output
-	var diff strings.Builder
-	headerfmt := pkg.Blue.Add(color.Underline).SprintfFunc()
-	// print input data
-	fmt.Fprintln(&diff, headerfmt("Input"))
-	fmt.Fprintln(&diff, inp)
+// Compare reports whether (validated) out and ans have equal
+// tokens in each line, with tokens compared by equal
+func Compare(out, ans string, equal func(string, string) bool) bool {
+	line, _, _, _ := firstDiff(out, ans, equal)
+	return line == 0
+}
 
-	// break output into lines
-	str1 := strings.Split(out, "\n")
-	str2 := strings.Split(ans, "\n")
-	// equalize string lengths
-	if len(str1) < len(str2) {
-		str1 = append(str1, make([]string, len(str2)-len(str1))...)
-	} else {
-		str2 = append(str2, make([]string, len(str1)-len(str2))...)
+// EqualTokens reports whether tokens out and ans are equal. Tokens
+// that parse as floats (and aren't both integers) are equal if their
+// absolute or relative (to ans) error doesn't exceed absEps / relEps
+func EqualTokens(out, ans string, absEps, relEps float64) bool {
+	if out == ans {
+		return true
+	} else if isInteger.MatchString(out) && isInteger.MatchString(ans) {
+		// integers are compared exactly
+		return false
 	}
-
-	// print output diff data
-	tbl := uitable.New()
-	tbl.Separator = " | "
-
-	tbl.AddRow(headerfmt("Actual Output"), headerfmt("Expected Output"))
-	// iterate over every row of outputs
-	for i := 0; i < len(str1); i++ {
-		tbl.AddRow(str1[i], str2[i])
+	a, errA := strconv.ParseFloat(out, 64)
+	b, errB := strconv.ParseFloat(ans, 64)
+	if errA != nil || errB != nil || math.IsNaN(a) || math.IsNaN(b) {
+		return false
+	} else if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
 	}
-	fmt.Fprintln(&diff, tbl)
-	fmt.Fprintln(&diff)
+	diff := math.Abs(a - b)
+	return diff <= absEps || diff <= relEps*math.Abs(b)
+}
+
+// PrintTranscript returns input data, and then the
+// data exchanged between solution and interactor
+func PrintTranscript(inp, transcript string) string {
+	var data strings.Builder
+	headerfmt := color.New(color.FgBlue, color.Underline).SprintfFunc()
+	// print input data
+	fmt.Fprintln(&data, headerfmt("Input"))
+	fmt.Fprintln(&data, inp)
+	// print transcript (> solution, < interactor)
+	fmt.Fprintln(&data, headerfmt("Transcript"))
+	fmt.Fprintln(&data, transcript)
 
-	return diff.String()
+	return data.String()
 }
@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package cln
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// peakMemory returns peak resident memory (in KB)
+// used by the exited process of state
+func peakMemory(state *os.ProcessState) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if ok == false {
+		return 0
+	}
+	// maxrss is measured in bytes on darwin
+	if runtime.GOOS == "darwin" {
+		return int64(rusage.Maxrss) / 1024
+	}
+	return int64(rusage.Maxrss)
+}
@@ -0,0 +1,12 @@
+package cln
+
+import (
+	"os"
+)
+
+// peakMemory returns peak resident memory (in KB)
+// used by the exited process of state. Measuring
+// memory isn't supported on windows (returns 0)
+func peakMemory(state *os.ProcessState) int64 {
+	return 0
+}
@@ -7,7 +7,6 @@ import (
 	"bytes"
 	"fmt"
 	"net/url"
-	"path"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -28,15 +27,14 @@ type (
 	}
 )
 
-// WatchSubmissions finds all submissions in contest that matches query string
+// WatchSubmissions finds all submissions in status page (at link) that matches query string
 // query = problem to fetch all submissions in a particular problem (should be uppercase)
 // query = submitID to fetch submission of given submission id
 func WatchSubmissions(contest, query string, link url.URL) ([]Submission, error) {
 	// This implementation contains redirection prevention
 	c := cfg.Session.Client
 	c.CheckRedirect = pkg.RedirectCheck
-	// fetch all submissions in contest
-	link.Path = path.Join(link.Path, "my")
+	// fetch all submissions in status page
 	body, err := pkg.GetReqBody(&c, link.String())
 	if err != nil {
 		return nil, err
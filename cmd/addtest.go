@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	cln "cf/client"
+	pkg "cf/packages"
+
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// RunAddTest is called on running `cf add-test`
+func (opt Opts) RunAddTest() {
+	// input is read from stdin, unless specified otherwise
+	src := opt.Input
+	if src == "" && opt.Editor == false {
+		src = "-"
+	}
+	if src == "-" && opt.Answer == "-" {
+		pkg.PrintError(fmt.Errorf("Input and answer can't both be read from stdin"),
+			"Failed to add test")
+	}
+	inp, err := readTest(src, "Test input:")
+	pkg.PrintError(err, "Failed to read test input")
+	if verdict, msg := checkInput(inp); verdict != "" {
+		pkg.PrintError(fmt.Errorf("%v%v", verdict, note(msg)), "Invalid test input")
+	}
+
+	// find expected output of test (if any)
+	ans := ""
+	if opt.FromSol == true {
+		ans = opt.solve(inp)
+	} else if opt.Answer != "" || opt.Editor == true {
+		ans, err = readTest(opt.Answer, "Expected output:")
+		pkg.PrintError(err, "Failed to read expected output")
+	}
+
+	name, err := cln.SaveTest(inp, ans)
+	pkg.PrintError(err, "Failed to save test")
+	pkg.Log.Success("Saved test " + name)
+	if ans == "" {
+		pkg.Log.Notice("Test has no expected output")
+	}
+	return
+}
+
+// readTest reads test data from file (stdin if src is '-')
+// or from the user's editor if src is empty. Non-empty
+// data is terminated with a newline
+func readTest(src, msg string) (string, error) {
+	var data []byte
+	var err error
+	switch src {
+	case "-":
+		data, err = ioutil.ReadAll(os.Stdin)
+	case "":
+		text := ""
+		err = survey.AskOne(&survey.Editor{
+			Message:  msg,
+			FileName: "*.txt",
+		}, &text)
+		data = []byte(text)
+	default:
+		data, err = ioutil.ReadFile(src)
+	}
+	text := string(data)
+	if text != "" && strings.HasSuffix(text, "\n") == false {
+		text += "\n"
+	}
+	return text, err
+}
+
+// solve runs selected solution against inp and returns its output
+func (opt Opts) solve(inp string) string {
+	// find code file to run
+	file, err := selSourceFile(cln.FindSourceFiles(opt.File))
+	pkg.PrintError(err, "Failed to select source file")
+	// find template configs to use
+	t, err := selTmpltConfig(cln.FindTmpltsConfig(file))
+	pkg.PrintError(err, "Failed to select template configuration")
+
+	e := Env{
+		Contest:   opt.contest,
+		Problem:   opt.problem,
+		Group:     opt.group,
+		ContClass: opt.contClass,
+		File:      file,
+	}
+
+	opt.setLimits(*t)
+	// run prescript
+	out, err := compile(*t, e)
+	if err != nil {
+		compileError(os.Stdout, out, err)
+		os.Exit(0)
+	}
+	fmt.Fprint(os.Stderr, out)
+
+	pkg.Log.Info("Running " + file + " to find expected output")
+	_, out, stderr, err := cln.ExecScript(e.ReplScript(t.Script, t.Shell), inp, opt.timeout(), nil)
+	fmt.Fprint(os.Stderr, stderr)
+	// run postscript
+	runScript(t.PostScript, t.Shell, e)
+	pkg.PrintError(err, "Solution failed on test input")
+	return out
+}
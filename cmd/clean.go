@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	cfg "cf/config"
+	pkg "cf/packages"
+
+	"os"
+)
+
+// RunClean is called on running `cf clean`
+func RunClean() {
+	// remove all cached build files
+	err := os.RemoveAll(cfg.CacheDir)
+	pkg.PrintError(err, "Failed to clear build cache")
+	os.MkdirAll(cfg.CacheDir, os.ModePerm)
+
+	pkg.Log.Success("Build cache cleared")
+	return
+}
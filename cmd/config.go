@@ -9,6 +9,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/mitchellh/go-homedir"
@@ -162,6 +164,39 @@ func addTmplt() {
 					"For example, 'rm a.out', 'del ${fileBase}', etc\n" +
 					"Can be left blank, if cleanup is required/desired",
 			},
+		}, {
+			Name: "checker",
+			Prompt: &survey.Input{
+				Message: "Checker:",
+				Help: "Script to run testlib style checker, to validate output\n" +
+					"Run with files of input, output and answer as arguments\n" +
+					"For example, '/opt/checkers/wcmp', 'python3 /opt/checkers/perm.py', etc\n" +
+					"Can be left blank, to compare output with expected output",
+			},
+		}, {
+			Name: "shell",
+			Prompt: &survey.Confirm{
+				Message: "Run scripts through shell?",
+				Help: "If set, scripts (except checker) are run through the system shell (sh -c / cmd /c)\n" +
+					"Required for scripts with pipes, redirections, '&&' chains, etc\n" +
+					"Else, scripts are parsed (with quoting rules) and run directly",
+				Default: false,
+			},
+		}, {
+			Name: "timefactor",
+			Prompt: &survey.Input{
+				Message: "Time limit factor:",
+				Help: "Multiplier of time limit of tests, for languages needing extra time\n" +
+					"For example, '1' for C++, '2' for Java, '3' for Python, etc",
+				Default: "1",
+			},
+			Validate: func(ans interface{}) error {
+				val, err := strconv.ParseFloat(ans.(string), 64)
+				if err != nil || val <= 0 {
+					return fmt.Errorf("factor should be a positive number")
+				}
+				return nil
+			},
 		},
 	}, &tmplt)
 	pkg.PrintError(err, "")
@@ -215,6 +250,7 @@ func miscPrefs() {
 			"Set host domain",
 			"Set proxy",
 			"Set workspace name",
+			"Add test file pattern",
 		},
 	}, &choice)
 	pkg.PrintError(err, "")
@@ -278,6 +314,40 @@ func miscPrefs() {
 				"Current configured workspace name: " + cfg.Settings.WSName,
 		}, &cfg.Settings.WSName, survey.WithValidator(survey.Required))
 		pkg.PrintError(err, "")
+
+	case 5:
+		// pattern must contain exactly one '*'
+		validate := func(ans interface{}) error {
+			if strings.Count(ans.(string), "*") != 1 {
+				return fmt.Errorf("pattern should contain exactly one '*'")
+			}
+			return nil
+		}
+		pattern := cfg.TestPattern{}
+		err := survey.Ask([]*survey.Question{
+			{
+				Name: "input",
+				Prompt: &survey.Input{
+					Message: "Input file pattern:",
+					Help: "Glob pattern of test input files, relative to problem folder\n" +
+						"For example, '*.in', 'input/*', 'tests/*.txt', etc",
+				},
+				Validate: validate,
+			}, {
+				Name: "output",
+				Prompt: &survey.Input{
+					Message: "Answer file pattern:",
+					Help: "Pattern of answer file of each input file. The '*' is\n" +
+						"replaced by the value matched by '*' in the input pattern\n" +
+						"For example, '*.ans', 'output/*', 'tests/*.a.txt', etc",
+				},
+				Validate: validate,
+			},
+		}, &pattern)
+		pkg.PrintError(err, "")
+		// new pattern has highest priority (used to create tests)
+		cfg.Settings.TestPatterns = append([]cfg.TestPattern{pattern},
+			cfg.Settings.TestPatterns...)
 	}
 	cfg.SaveSettings()
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,6 +20,20 @@ func (opt Opts) RunFetch() {
 		pkg.Log.Error("No contest id found")
 		return
 	}
+	// problemset problems are fetched from their own page
+	if opt.problemset == true || opt.contClass == "acmsguru" {
+		if opt.problem == "" {
+			pkg.Log.Error("No problem id found")
+			return
+		}
+		name := opt.contest + strings.ToUpper(opt.problem)
+		if opt.contClass == "acmsguru" {
+			name = opt.contClass + " " + opt.problem
+		}
+		pkg.Log.Info("Fetching problem " + name)
+		opt.saveProblems([]string{opt.problem}, make(map[string]cln.ProblemPage))
+		return
+	}
 	// fetch countdown info
 	pkg.Log.Info("Fetching details of " + opt.contClass + " " + opt.contest)
 	dur, err := cln.FindCountdown(opt.contest, opt.link)
@@ -39,58 +55,78 @@ func (opt Opts) RunFetch() {
 	pkg.PrintError(err, "Extraction of contest problems failed")
 
 	// Fetch all tests from problems page
-	splInp, splOut, err := cln.FetchTests(opt.contest, "", opt.link)
+	fetched, err := cln.FetchTests(opt.contest, "", opt.link)
 	pkg.PrintError(err, "Failed to extract sample tests")
-	// no sample tests found, try parsing from each problem
-	if len(splInp) == 0 {
+	if len(fetched) == 0 {
 		pkg.Log.Warning("Failed to fetch tests from problems page")
+	}
+	opt.saveProblems(probs, fetched)
+	return
+}
+
+// saveProblems saves tests and data of problems (to be fetched) in
+// probs to workspace. Problems missing from fetched are fetched
+// from their own pages
+func (opt Opts) saveProblems(probs []string, fetched map[string]cln.ProblemPage) {
+	// problems that couldn't be fetched
+	failed := make(map[string]bool)
+	// problems missing from problems page, try parsing from their pages
+	var missing []string
+	for _, prob := range probs {
+		if _, ok := fetched[prob]; !ok && (opt.problem == "" || prob == opt.problem) {
+			missing = append(missing, prob)
+		}
+	}
+	if len(missing) != 0 {
 		pkg.Log.Info("Fetching from page of every problem")
-		pkg.Log.Notice("Please be patient")
-		// iterate over all present problems
-		for _, prob := range probs {
-			// Problem isn't specified to be fetched
-			if opt.problem != "" && prob != opt.problem {
-				// enter blank tests (as they aren't required)
-				splInp = append(splInp, make([]string, 0))
-				splOut = append(splOut, make([]string, 0))
-				continue
-			}
-			probInp, probOut, err := cln.FetchTests(opt.contest, prob, opt.link)
-			pkg.PrintError(err, "Failed to extract sample tests of "+prob)
-			// append sample tests to slice
-			splInp = append(splInp, probInp...)
-			splOut = append(splOut, probOut...)
-			// if problem is pdf format (can't extract tests)
-			if len(probInp) == 0 {
-				pkg.Log.Warning("Unable to extract test(s) - " + prob)
-				splInp = append(splInp, make([]string, 0))
-				splOut = append(splOut, make([]string, 0))
+		// results are collected in order of problems
+		results := opt.fetchPages(missing)
+		for i, prob := range missing {
+			p := <-results[i]
+			if p.err != nil {
+				// report failure, and continue with rest
+				pkg.Log.Error("Failed to fetch " + prob + ": " + p.err.Error())
+				failed[prob] = true
+			} else if pp, ok := p.probs[prob]; ok {
+				fetched[prob] = pp
 			}
 		}
 	}
 
 	// iterate over fetched problems tests
-	for i, prob := range probs {
-		// Problem isn't specified to be fetched
-		if opt.problem != "" && prob != opt.problem {
+	for _, prob := range probs {
+		// Problem isn't specified to be fetched (or failed)
+		if (opt.problem != "" && prob != opt.problem) || failed[prob] == true {
 			continue
 		}
 		// create problem folder
-		path := opt.dirPath
-		if opt.group == "" {
-			path = filepath.Join(path, opt.contClass, opt.contest, prob)
-		} else {
-			path = filepath.Join(path, opt.contClass, opt.group, opt.contest, prob)
-		}
+		path := opt.probPath(prob)
 		os.MkdirAll(path, os.ModePerm)
+		pp, ok := fetched[prob]
+		if !ok || len(pp.Inputs) == 0 {
+			// if problem is pdf format (can't extract tests)
+			pkg.Log.Warning("Unable to extract test(s) - " + prob)
+		}
 		// create tests
-		for x := 0; x < len(splInp[i]); x++ {
-			// create input file (form x.in)
-			pkg.CreateFile(splInp[i][x], fmt.Sprintf("%v/%d.in", path, x))
-			// create output file (form x.ans)
-			pkg.CreateFile(splOut[i][x], fmt.Sprintf("%v/%d.out", path, x))
+		for x := 0; x < len(pp.Inputs) && x < len(pp.Outputs); x++ {
+			// create input / output file (form x.in / x.out)
+			inpFile, outFile := cln.TestFiles(path, strconv.Itoa(x))
+			os.MkdirAll(filepath.Dir(inpFile), os.ModePerm)
+			os.MkdirAll(filepath.Dir(outFile), os.ModePerm)
+			pkg.CreateFile(pp.Inputs[x], inpFile)
+			pkg.CreateFile(pp.Outputs[x], outFile)
+		}
+		// save problem metadata (if parsed)
+		if pp.Data.Name != "" {
+			if err := cln.SaveProblem(path, pp.Data); err != nil {
+				pkg.Log.Warning("Failed to save problem data - " + prob + ": " + err.Error())
+			}
+			// save statement for offline viewing
+			if err := cln.SaveStatement(path, pp.Data, opt.link); err != nil {
+				pkg.Log.Warning("Failed to save statement - " + prob)
+			}
 		}
-		pkg.Log.Success(fmt.Sprintf("Fetched %d test(s) - %v", len(splInp[i]), prob))
+		pkg.Log.Success(fmt.Sprintf("Fetched %d test(s) - %v", len(pp.Inputs), prob))
 		// generate code files if specified
 		idx := cfg.Settings.DfltTmplt
 		if cfg.Settings.GenOnFetch == true && idx != -1 {
@@ -101,10 +137,41 @@ func (opt Opts) RunFetch() {
 			oo.GenCode(&cfg.Templates[idx], path)
 		}
 	}
-
 	return
 }
 
+// number of problem pages fetched concurrently
+const fetchJobs = 4
+
+// page holds data extracted from page of a problem
+type page struct {
+	probs map[string]cln.ProblemPage
+	err   error
+}
+
+// fetchPages concurrently fetches page of each problem in probs
+// Result of each problem is sent to its channel, in order of probs
+func (opt Opts) fetchPages(probs []string) []chan page {
+	results := make([]chan page, len(probs))
+	queue := make(chan int, len(probs))
+	for i := range probs {
+		results[i] = make(chan page, 1)
+		queue <- i
+	}
+	close(queue)
+	// run pool of workers fetching pages from queue
+	for w := 0; w < fetchJobs; w++ {
+		go func() {
+			for i := range queue {
+				var p page
+				p.probs, p.err = cln.FetchTests(opt.contest, opt.pageID(probs[i]), opt.link)
+				results[i] <- p
+			}
+		}()
+	}
+	return results
+}
+
 // startCountdown starts countdown of dur seconds
 func startCountdown(dur int64) {
 	// run timer till it runs out
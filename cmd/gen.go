@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	cln "cf/client"
 	cfg "cf/config"
 	pkg "cf/packages"
 
@@ -50,6 +51,16 @@ func (opt Opts) GenCode(t *cfg.Template, path string) {
 		Group:     opt.group,
 		ContClass: opt.contClass,
 	}
+	// set fetched problem data (if any)
+	if prob, err := cln.LoadProblem(path); err == nil {
+		e.Name, e.InpFile, e.OutFile = prob.Name, prob.Input, prob.Output
+		e.TimeLimit = strconv.FormatFloat(prob.TimeLimit, 'f', -1, 64)
+		e.MemLimit = strconv.Itoa(prob.MemoryLimit)
+		if prob.FileIO() == true {
+			pkg.Log.Warning(fmt.Sprintf("Problem uses file input / output (%v / %v)",
+				prob.Input, prob.Output))
+		}
+	}
 
 	source := e.ReplPlaceholder(string(file))
 
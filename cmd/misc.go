@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	cln "cf/client"
 	cfg "cf/config"
 	pkg "cf/packages"
 
@@ -10,6 +11,7 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -26,23 +28,50 @@ type (
 		Config  bool `docopt:"config"`
 		Gen     bool `docopt:"gen"`
 		Open    bool `docopt:"open"`
+		Show    bool `docopt:"show"`
 		Fetch   bool `docopt:"fetch"`
 		Test    bool `docopt:"test"`
+		Stress  bool `docopt:"stress"`
+		AddTest bool `docopt:"add-test"`
 		Submit  bool `docopt:"submit"`
 		Watch   bool `docopt:"watch"`
 		Pull    bool `docopt:"pull"`
+		Clean   bool `docopt:"clean"`
 		Upgrade bool `docopt:"upgrade"`
 
 		Info []string `docopt:"<info>"`
 
-		All    bool   `docopt:"--all"`
-		File   string `docopt:"--file"`
-		IgCase bool   `docopt:"--ignore-case"`
-		Exp    int    `docopt:"--ignore-exp"`
-		Tl     int    `docopt:"--time-limit"`
-		SubCnt int    `docopt:"--submissions"`
-		Handle string `docopt:"--handle"`
-		Custom bool   `docopt:"--custom"`
+		All    bool    `docopt:"--all"`
+		File   string  `docopt:"--file"`
+		IgCase bool    `docopt:"--ignore-case"`
+		AbsEps float64 `docopt:"--abs-eps"`
+		RelEps float64 `docopt:"--rel-eps"`
+		Tl     float64 `docopt:"--time-limit"`
+		MemLim int     `docopt:"--memory-limit"`
+		SubCnt int     `docopt:"--submissions"`
+		Handle string  `docopt:"--handle"`
+		Custom bool    `docopt:"--custom"`
+		Jobs   int     `docopt:"--jobs"`
+		Diff   string  `docopt:"--diff"`
+		Report string  `docopt:"--report"`
+		Live   bool    `docopt:"--watch"`
+		Only   string  `docopt:"--only"`
+		Failed bool    `docopt:"--failed"`
+		Stderr string  `docopt:"--stderr"`
+
+		Sandbox bool `docopt:"--sandbox"`
+
+		Generator string `docopt:"--generator"`
+		Brute     string `docopt:"--brute"`
+		Iter      int    `docopt:"--iterations"`
+
+		Editor  bool   `docopt:"--editor"`
+		Input   string `docopt:"--input"`
+		Answer  string `docopt:"--answer"`
+		FromSol bool   `docopt:"--from-solution"`
+
+		// fetched data of problem
+		prob cln.ProblemData
 
 		contest   string
 		problem   string
@@ -50,6 +79,8 @@ type (
 		contClass string
 		dirPath   string
 		link      url.URL
+		// problem is referenced from the problemset
+		problemset bool
 	}
 
 	// Env are global (generic and non-genric) variables
@@ -67,11 +98,24 @@ type (
 		Idx       string `env:"${idx}"`
 		File      string `env:"${file}"`
 		FileBase  string `env:"${fileBase}"`
+
+		// fetched problem data
+		Name      string `env:"${name}"`
+		TimeLimit string `env:"${timeLimit}"`
+		MemLimit  string `env:"${memLimit}"`
+		InpFile   string `env:"${inputFile}"`
+		OutFile   string `env:"${outputFile}"`
 	}
 )
 
+// contest id of problems in acmsguru problemset
+const acmsguruID = "99999"
+
+// matches compact problem codes (like 1234c / 1234c2)
+var probCode = regexp.MustCompile(`^(\d+)([a-zA-Z]\d*)$`)
+
 // FindContestData extracts contest / problem id from path
-// and also determines the class (contest / gym) from the contest id
+// and also determines the class (contest / gym / group / acmsguru)
 func (opt *Opts) FindContestData() {
 	// path to current directory
 	currPath, _ := os.Getwd()
@@ -98,6 +142,12 @@ func (opt *Opts) FindContestData() {
 					opt.problem = data[i+3]
 					currPath = clean(i)
 					break
+				} else if data[i+1] == "acmsguru" {
+					opt.contClass = data[i+1]
+					opt.contest = acmsguruID
+					opt.problem = data[i+2]
+					currPath = clean(i)
+					break
 				} else if data[i+1] == "group" {
 					opt.contClass = data[i+1]
 					opt.group = data[i+2]
@@ -117,7 +167,20 @@ func (opt *Opts) FindContestData() {
 		// iterate over each part of url and
 		// find first part matching criteria
 		for i := 0; i < sz; i++ {
-			if data[i] == "contest" || data[i] == "gym" {
+			if data[i] == "problemsets" && data[i+1] == "acmsguru" {
+				// acmsguru problem (problem/99999/100)
+				opt.contClass = data[i+1]
+				opt.contest = acmsguruID
+				opt.problem = data[i+4]
+				break
+			} else if data[i] == "problemset" && data[i+1] == "problem" {
+				// problemset problem (same layout as contest)
+				opt.contClass = "contest"
+				opt.contest = data[i+2]
+				opt.problem = data[i+3]
+				opt.problemset = true
+				break
+			} else if data[i] == "contest" || data[i] == "gym" {
 				opt.contClass = data[i]
 				opt.contest = data[i+1]
 				opt.problem = data[i+3]
@@ -133,12 +196,20 @@ func (opt *Opts) FindContestData() {
 	} else {
 		// parse from command line args (for example, 1234 c2)
 		data := append(opt.Info, make([]string, 10)...)
-		if val, err := strconv.Atoi(data[0]); err == nil {
-			if val <= 100000 {
-				opt.contClass = "contest"
-			} else {
-				opt.contClass = "gym"
-			}
+		if data[0] == "acmsguru" {
+			// acmsguru problem (for example, acmsguru 100)
+			opt.contClass = data[0]
+			opt.contest = acmsguruID
+			opt.problem = data[1]
+		} else if code := probCode.FindStringSubmatch(data[0]); code != nil {
+			// compact problem code (for example, 1234c)
+			opt.contClass = contClassOf(code[1])
+			opt.contest = code[1]
+			opt.problem = code[2]
+			// gym problems aren't in the problemset
+			opt.problemset = opt.contClass == "contest"
+		} else if _, err := strconv.Atoi(data[0]); err == nil {
+			opt.contClass = contClassOf(data[0])
 			opt.contest = data[0]
 			opt.problem = data[1]
 		} else if len(data[0]) == 10 {
@@ -160,6 +231,9 @@ func (opt *Opts) FindContestData() {
 	if opt.contClass == "contest" || opt.contClass == "gym" {
 		// not group, regular parsing
 		opt.link.Path = path.Join(opt.link.Path, opt.contClass, opt.contest)
+	} else if opt.contClass == "acmsguru" {
+		// acmsguru has its own problemset
+		opt.link.Path = path.Join(opt.link.Path, "problemsets", opt.contClass)
 	} else if opt.contClass == "group" {
 		// append group value to link
 		opt.link.Path = path.Join(opt.link.Path, opt.contClass,
@@ -168,18 +242,81 @@ func (opt *Opts) FindContestData() {
 	return
 }
 
+// contClassOf returns class (contest / gym) of contest id
+func contClassOf(contest string) string {
+	if val, _ := strconv.Atoi(contest); val <= 100000 {
+		return "contest"
+	}
+	return "gym"
+}
+
+// problemsetLink returns link to problem in problemset
+func (opt Opts) problemsetLink() url.URL {
+	link, _ := url.Parse(cfg.Settings.Host)
+	link.Path = path.Join(link.Path, "problemset", "problem",
+		opt.contest, strings.ToUpper(opt.problem))
+	return *link
+}
+
+// pageID returns id of page of problem prob (relative to
+// problem/ of contest link)
+func (opt Opts) pageID(prob string) string {
+	if opt.contClass == "acmsguru" {
+		return path.Join(opt.contest, prob)
+	}
+	return prob
+}
+
+// statusLink returns link to page listing submissions of
+// current user (in contest)
+func (opt Opts) statusLink() url.URL {
+	link := opt.link
+	if opt.contClass == "acmsguru" {
+		// acmsguru submissions are listed in submissions of user
+		link, _ := url.Parse(cfg.Settings.Host)
+		link.Path = path.Join(link.Path, "submissions", cfg.Session.Handle)
+		return *link
+	}
+	link.Path = path.Join(link.Path, "my")
+	return link
+}
+
+// probPath returns path to folder of problem prob in workspace
+func (opt Opts) probPath(prob string) string {
+	if opt.contClass == "acmsguru" {
+		// acmsguru problems aren't grouped in contests
+		return filepath.Join(opt.dirPath, opt.contClass, prob)
+	} else if opt.group == "" {
+		return filepath.Join(opt.dirPath, opt.contClass, opt.contest, prob)
+	}
+	return filepath.Join(opt.dirPath, opt.contClass, opt.group, opt.contest, prob)
+}
+
 // ReplPlaceholder replaces all global variables in text
 // with their respective values. Non-generic are passed as map
 func (e Env) ReplPlaceholder(text string) string {
+	return e.replace(text, false)
+}
+
+// ReplScript replaces all global variables in script, quoting values
+// to be parsed as single arguments. Script is run through the system
+// shell (sh -c / cmd /c) if shell is set
+func (e Env) ReplScript(script string, shell bool) string {
+	script = e.replace(script, true)
+	if shell == true {
+		script = cln.ShellScript(script)
+	}
+	return script
+}
+
+// replace replaces global variables in text with their
+// values, quoted as script arguments if script is set
+func (e Env) replace(text string, script bool) string {
 	// set date/time
 	e.handle = cfg.Session.Handle
 	e.date = time.Now().Format("02-01-06")
 	e.time = time.Now().Format("15:04:05")
 
-	// replace string data
-	repl := func(old, new string) string {
-		return strings.ReplaceAll(text, old, new)
-	}
 	// omit ${idx} = 0
 	if e.Idx == "0" {
 		e.Idx = ""
@@ -187,15 +324,19 @@ func (e Env) ReplPlaceholder(text string) string {
 	// extract file name from ${file} value
 	e.FileBase = strings.TrimSuffix(e.File, filepath.Ext(e.File))
 
-	// iterate over struct and replace variables
+	// iterate over struct and find values of variables
+	vals := make(map[string]string)
 	t := reflect.TypeOf(e)
 	v := reflect.ValueOf(e)
 	for i := 0; i < v.NumField(); i++ {
-		tag := t.Field(i).Tag.Get("env")
-		val := v.Field(i).String()
-		text = repl(tag, val)
+		vals[t.Field(i).Tag.Get("env")] = v.Field(i).String()
+	}
+	if script == true {
+		return cln.ReplaceArgs(text, vals)
+	}
+	for tag, val := range vals {
+		text = strings.ReplaceAll(text, tag, val)
 	}
-
 	return text
 }
 
@@ -280,4 +421,7 @@ Parsing structure of problems
     - ${group}
       - ${contest}
         - ${problem}
+
+  - acmsguru
+    - ${problem}
 */
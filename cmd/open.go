@@ -17,10 +17,12 @@ func (opt Opts) RunOpen() {
 	}
 	link := opt.link
 	// open problems page (all problems)
-	if opt.problem == "" {
+	if opt.problemset == true {
+		link = opt.problemsetLink()
+	} else if opt.problem == "" {
 		link.Path = path.Join(link.Path, "problems")
 	} else {
-		link.Path = path.Join(link.Path, "problem", opt.problem)
+		link.Path = path.Join(link.Path, "problem", opt.pageID(opt.problem))
 	}
 	// open page in default browser
 	browserOpen(link.String())
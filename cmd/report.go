@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+type (
+	// record is the report data of a test
+	record struct {
+		Test     string  `json:"test"`
+		Verdict  string  `json:"verdict"`
+		Message  string  `json:"message,omitempty"`
+		Elapsed  float64 `json:"elapsed"`
+		CPUTime  float64 `json:"cpu_time"`
+		Memory   int64   `json:"memory"`
+		ExitCode int     `json:"exit_code"`
+		Output   string  `json:"output"`
+		Answer   string  `json:"expected"`
+		Diff     string  `json:"diff,omitempty"`
+		Stderr   string  `json:"stderr,omitempty"`
+	}
+
+	// junitSuite and junitCase hold report data
+	// of tests in junit xml format
+	junitSuite struct {
+		XMLName  xml.Name    `xml:"testsuite"`
+		Name     string      `xml:"name,attr"`
+		Tests    int         `xml:"tests,attr"`
+		Failures int         `xml:"failures,attr"`
+		Time     float64     `xml:"time,attr"`
+		Cases    []junitCase `xml:"testcase"`
+	}
+	junitCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Time      float64       `xml:"time,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+		SystemOut string        `xml:"system-out"`
+		SystemErr string        `xml:"system-err,omitempty"`
+	}
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Data    string `xml:",chardata"`
+	}
+)
+
+// report returns results of tests formatted as
+// specified by the --report flag (json / junit)
+func (opt Opts) report(results []result) (string, error) {
+	// diff output shouldn't contain color codes
+	color.NoColor = true
+
+	var records []record
+	for _, r := range results {
+		records = append(records, record{
+			Test:     r.Test,
+			Verdict:  r.Verdict,
+			Message:  r.Message,
+			Elapsed:  r.Usage.Elapsed.Seconds(),
+			CPUTime:  r.Usage.CPUTime.Seconds(),
+			Memory:   r.Usage.Memory,
+			ExitCode: r.ExitCode,
+			Output:   r.Output,
+			Answer:   r.Answer,
+			Diff:     r.diff(opt),
+			Stderr:   r.Stderr,
+		})
+	}
+
+	switch opt.Report {
+	case "json":
+		data, err := json.MarshalIndent(records, "", "\t")
+		return string(data), err
+
+	case "junit":
+		suite := junitSuite{Name: "cf test", Tests: len(records)}
+		for _, r := range records {
+			c := junitCase{
+				Name:      "#" + r.Test,
+				ClassName: opt.contest + opt.problem,
+				Time:      r.Elapsed,
+				SystemOut: r.Output,
+				SystemErr: r.Stderr,
+			}
+			if r.Verdict != "AC" && r.Verdict != "NA" {
+				suite.Failures++
+				c.Failure = &junitFailure{
+					Message: r.Verdict + note(r.Message),
+					Type:    r.Verdict,
+					Data:    r.Diff,
+				}
+			}
+			suite.Time += r.Elapsed
+			suite.Cases = append(suite.Cases, c)
+		}
+		data, err := xml.MarshalIndent(suite, "", "\t")
+		return xml.Header + string(data), err
+	}
+	return "", fmt.Errorf("Invalid report format %v (use json / junit)", opt.Report)
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	cln "cf/client"
+	pkg "cf/packages"
+
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// RunShow is called on running `cf show`
+func (opt Opts) RunShow() {
+	// check if problem id is present
+	if opt.problem == "" {
+		pkg.Log.Error("No problem id found")
+		return
+	}
+	// read statement saved by fetch
+	file := filepath.Join(opt.probPath(opt.problem), "statement.md")
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		pkg.Log.Error("No statement found for problem " + opt.problem)
+		pkg.Log.Notice("Fetch the problem (cf fetch) to save its statement")
+		return
+	}
+	fmt.Print(cln.RenderMarkdown(string(data)))
+	return
+}
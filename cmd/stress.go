@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	cln "cf/client"
+	pkg "cf/packages"
+
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// RunStress is called on running `cf stress`
+func (opt Opts) RunStress() {
+	// find code file to test
+	file, err := selSourceFile(cln.FindSourceFiles(opt.File))
+	pkg.PrintError(err, "Failed to select source file")
+	// find template configs to use
+	t, err := selTmpltConfig(cln.FindTmpltsConfig(file))
+	pkg.PrintError(err, "Failed to select template configuration")
+
+	e := Env{
+		Contest:   opt.contest,
+		Problem:   opt.problem,
+		Group:     opt.group,
+		ContClass: opt.contClass,
+		File:      file,
+	}
+
+	opt.setLimits(*t)
+	if opt.Stderr != "show" && opt.Stderr != "hide" && opt.Stderr != "file" {
+		pkg.Log.Error("Invalid stderr mode " + opt.Stderr + " (use show / hide / file)")
+		return
+	}
+	if opt.Sandbox == true && t.Shell == true {
+		pkg.Log.Error("Sandbox mode doesn't support templates run through shell")
+		return
+	}
+
+	// run prescript
+	out, err := compile(*t, e)
+	if err != nil {
+		compileError(os.Stdout, out, err)
+		return
+	}
+	fmt.Fprint(os.Stderr, out)
+	// find checker to validate output with (if any)
+	checker := findChecker(*t, e)
+
+	// replace placeholders in scripts
+	script := e.ReplScript(t.Script, t.Shell)
+	gen := e.ReplScript(opt.Generator, false)
+	brute := e.ReplScript(opt.Brute, false)
+
+	pkg.LiveUI.Start()
+	for seed := 1; seed <= opt.Iter; seed++ {
+		pkg.LiveUI.Print(fmt.Sprintf("Running test with seed %d", seed))
+		// generate input, with seed passed as argument
+		_, inp, stderr, err := cln.ExecScript(gen+" "+strconv.Itoa(seed), "", opt.timeout(), nil)
+		if err != nil {
+			fmt.Fprint(os.Stderr, stderr)
+		}
+		pkg.PrintError(err, "Generator failed on seed "+strconv.Itoa(seed))
+		if verdict, msg := checkInput(inp); verdict != "" {
+			pkg.PrintError(fmt.Errorf("%v%v", verdict, note(msg)),
+				"Invalid input generated on seed "+strconv.Itoa(seed))
+		}
+		// run brute force solution to find answer
+		_, ans, stderr, err := cln.ExecScript(brute, inp, opt.timeout(), nil)
+		if err != nil {
+			fmt.Fprint(os.Stderr, stderr)
+		}
+		pkg.PrintError(err, "Brute force solution failed on seed "+strconv.Itoa(seed))
+
+		// run solution and validate output
+		verdict, msg := "", ""
+		usage, out, stderr, err := cln.ExecScript(script, inp, opt.timeout(), opt.sandbox())
+		switch {
+		case opt.isTLE(usage):
+			verdict = "TLE"
+		case usage.Memory > int64(opt.MemLim)*1024:
+			verdict = "MLE"
+		case err == cln.ErrOutputLimit:
+			verdict = "OLE"
+		case err == cln.ErrSecurity:
+			verdict = "SV"
+		case err != nil:
+			verdict, msg = "RTE", cln.ExitReason(err)
+		default:
+			verdict, msg, err = opt.checkOutput(checker, inp, out, ans)
+			pkg.PrintError(err, "Checker failed on seed "+strconv.Itoa(seed))
+		}
+		if verdict == "AC" {
+			continue
+		}
+
+		// save failing test and print diff
+		pkg.LiveUI.Print()
+		name, err := cln.SaveTest(inp, ans)
+		pkg.PrintError(err, "Failed to save failing test")
+		pkg.Red.Printf("Seed %d: %v .... %v%v\n", seed, verdict, usage.String(), note(msg))
+		pkg.Log.Notice("Saved failing test as test " + name)
+		out, ans = cln.Validator(out, ans, opt.IgCase)
+		pkg.Log.Info(cln.PrintDiff(inp, out, ans, opt.Diff, opt.equal))
+		opt.printStderr(os.Stdout, stderr)
+		opt.saveStderr(name, stderr)
+		// run postscript
+		runScript(t.PostScript, t.Shell, e)
+		return
+	}
+	pkg.LiveUI.Print()
+	pkg.Log.Success(fmt.Sprintf("Passed all %d test(s)", opt.Iter))
+
+	// run postscript
+	runScript(t.PostScript, t.Shell, e)
+	return
+}
@@ -5,6 +5,9 @@ import (
 	cfg "cf/config"
 	pkg "cf/packages"
 
+	"io/ioutil"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gosuri/uitable"
@@ -23,6 +26,17 @@ func (opt Opts) RunSubmit() {
 	// find template config to use
 	t, err := selTmpltConfig(cln.FindTmpltsConfig(file))
 	pkg.PrintError(err, "Failed to select template configuration")
+	// validate source against fetched problem data (if in problem folder)
+	if prob, err := cln.LoadProblem("."); err == nil && len(opt.Info) == 0 {
+		pkg.Log.Info("Problem: " + prob.Name)
+		source, _ := ioutil.ReadFile(file)
+		for _, name := range []string{prob.Input, prob.Output} {
+			if name != "" && strings.HasPrefix(name, "standard ") == false &&
+				strings.Contains(string(source), name) == false {
+				pkg.Log.Warning("Problem uses file " + name + ", not referenced in " + file)
+			}
+		}
+	}
 
 	// check login status
 	usr, err := cln.LoggedInUsr()
@@ -55,7 +69,15 @@ func (opt Opts) RunSubmit() {
 	}
 
 	// main submit code runs here
-	err = cln.Submit(opt.contest, opt.problem, t.LangID, file, opt.link)
+	if opt.problemset == true {
+		// submit through problemset form (if referenced from problemset)
+		link, _ := url.Parse(cfg.Settings.Host)
+		err = cln.SubmitProblemset(opt.contest, opt.problem, t.LangID, file, *link)
+	} else if opt.contClass == "acmsguru" {
+		err = cln.SubmitAcmsguru(opt.contest, opt.problem, t.LangID, file, opt.link)
+	} else {
+		err = cln.Submit(opt.contest, opt.problem, t.LangID, file, opt.link)
+	}
 	pkg.PrintError(err, "Failed to submit source code")
 	pkg.Log.Success("Submitted")
 	// watch submission verdict
@@ -67,13 +89,17 @@ func (opt Opts) RunSubmit() {
 func (opt Opts) watch() {
 	// infinite loop till verdicts declared
 	pkg.LiveUI.Start()
-	for query := opt.problem; ; {
+	for query := opt.pageID(opt.problem); ; {
 		// query param to fetch submitted code verdict and not latest verdict in prob
 		// fetch submission status from contest every second
 		start := time.Now()
 
-		data, err := cln.WatchSubmissions(opt.contest, query, opt.link)
+		data, err := cln.WatchSubmissions(opt.contest, query, opt.statusLink())
 		pkg.PrintError(err, "Failed to extract submissions in contest.")
+		if len(data) == 0 {
+			pkg.Log.Error("Submission not found in status page")
+			return
+		}
 		sub := data[0]
 		query = sub.ID
 
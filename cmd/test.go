@@ -5,9 +5,19 @@ import (
 	cfg "cf/config"
 	pkg "cf/packages"
 
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 )
 
 // RunTest is called on running `cf test`
@@ -28,79 +38,571 @@ func (opt Opts) RunTest() {
 		File:      file,
 	}
 
+	opt.setLimits(*t)
+	// validate diff mode and report format
+	if opt.Diff != "side" && opt.Diff != "unified" && opt.Diff != "token" && opt.Diff != "none" {
+		pkg.Log.Error("Invalid diff mode " + opt.Diff + " (use side / unified / token / none)")
+		return
+	}
+	if opt.Report != "" && opt.Report != "json" && opt.Report != "junit" {
+		pkg.Log.Error("Invalid report format " + opt.Report + " (use json / junit)")
+		return
+	}
+	if opt.Stderr != "show" && opt.Stderr != "hide" && opt.Stderr != "file" {
+		pkg.Log.Error("Invalid stderr mode " + opt.Stderr + " (use show / hide / file)")
+		return
+	}
+	if opt.Sandbox == true && t.Shell == true {
+		pkg.Log.Error("Sandbox mode doesn't support templates run through shell")
+		return
+	}
+	// notify of problem data, tests can't account for
+	if opt.prob.FileIO() == true {
+		pkg.Log.Warning(fmt.Sprintf("Problem uses file input / output (%v / %v)",
+			opt.prob.Input, opt.prob.Output))
+		pkg.Log.Notice("Tests are run with standard input / output")
+	}
+	if opt.prob.AnyValid == true && findChecker(*t, e) == "" {
+		pkg.Log.Notice("Problem may accept any valid answer. Configure a checker to validate output")
+	}
+
+	// re-run tests on modifications
+	if opt.Live == true {
+		opt.watchJudge(*t, e)
+		return
+	}
+
 	// run prescript
-	if t.PreScript != "" {
-		// replace placeholders in script
-		script := e.ReplPlaceholder(t.PreScript)
-		pkg.Log.Notice(script)
-		// run script with timer of 20 secs
-		_, _, err := cln.ExecScript(script, "", 1e9)
-		pkg.PrintError(err, "")
+	out, err := compile(*t, e)
+	if err != nil {
+		compileError(os.Stdout, out, err)
+		return
 	}
+	fmt.Fprint(os.Stderr, out)
 
-	if opt.Custom == false {
+	if opt.Custom == false && opt.prob.Interactive == false {
 		// run traditional judge
 		opt.tradJudge(*t, e)
 	} else {
+		if opt.Custom == false {
+			pkg.Log.Notice("Problem is interactive")
+		}
 		// run interactive / special judge
 		opt.spclJudge(*t, e)
 	}
 
 	// run postscript
-	if t.PostScript != "" {
-		// replace placeholders in script
-		script := e.ReplPlaceholder(t.PostScript)
+	runScript(t.PostScript, t.Shell, e)
+	return
+}
+
+// runScript replaces placeholders in (pre/post) script
+// and runs it (if non-empty). Exits on failure
+func runScript(script string, shell bool, e Env) {
+	if script == "" {
+		return
+	}
+	// replace placeholders in script
+	script = e.ReplScript(script, shell)
+	pkg.Log.Notice(script)
+	// run script with (practically) no time limit
+	_, _, stderr, err := cln.ExecScript(script, "", 1e9*time.Second, nil)
+	fmt.Fprint(os.Stderr, stderr)
+	pkg.PrintError(err, "")
+}
+
+// compile runs prescript of template (if any), reusing cached
+// build files if the source file hasn't been modified since.
+// Returns output of the prescript
+func compile(t cfg.Template, e Env) (string, error) {
+	if t.PreScript == "" {
+		return "", nil
+	}
+	// replace placeholders in script
+	script := e.ReplScript(t.PreScript, t.Shell)
+	key, err := cln.BuildKey(e.File, t.Alias, script)
+	if err != nil {
+		return "", err
+	}
+	out, cached, err := cln.ExecBuild(script, key)
+	if cached == true {
+		pkg.Log.Notice("Using cached build of " + e.File)
+	} else {
 		pkg.Log.Notice(script)
-		// run script with timer of 20 secs
-		_, _, err := cln.ExecScript(script, "", 1e9)
-		pkg.PrintError(err, "")
 	}
-	return
+	return out, err
+}
+
+// setLimits sets limits of tests not set by flags, to those in
+// fetched problem data (problem.json) or the defaults: 2 secs,
+// 256 MB and 1e-9 error. Time limit is scaled by template factor
+func (opt *Opts) setLimits(t cfg.Template) {
+	opt.prob, _ = cln.LoadProblem(".")
+	if opt.Tl == 0 {
+		opt.Tl = 2
+		if opt.prob.TimeLimit != 0 {
+			opt.Tl = opt.prob.TimeLimit
+		}
+	}
+	if t.TimeFactor > 0 {
+		opt.Tl *= t.TimeFactor
+	}
+	if opt.MemLim == 0 {
+		opt.MemLim = 256
+		if opt.prob.MemoryLimit != 0 {
+			opt.MemLim = opt.prob.MemoryLimit
+		}
+	}
+	if opt.AbsEps == 0 && opt.RelEps == 0 {
+		opt.AbsEps, opt.RelEps = 1e-9, 1e-9
+		if opt.prob.AbsEps != 0 || opt.prob.RelEps != 0 {
+			opt.AbsEps, opt.RelEps = opt.prob.AbsEps, opt.prob.RelEps
+		}
+	}
+}
+
+// timeout returns wall clock time after which solution is killed
+// (so idle / blocked solutions don't hang). TLE is otherwise
+// decided by the cpu time used by the solution
+func (opt Opts) timeout() time.Duration {
+	return time.Duration((2*opt.Tl + 1) * float64(time.Second))
+}
+
+// isTLE reports whether usage exceeds the time limit
+func (opt Opts) isTLE(u cln.Usage) bool {
+	return u.CPUTime.Seconds() >= opt.Tl || u.Elapsed >= opt.timeout()
+}
+
+// compileError writes output of failed prescript to w
+// (or the error running it, if there's no output)
+func compileError(w io.Writer, out string, err error) {
+	pkg.Red.Fprintln(w, "Compilation error")
+	if strings.TrimSpace(out) == "" {
+		out = cln.ExitReason(err) + "\n"
+	}
+	fmt.Fprint(w, out)
+}
+
+// sandbox returns resource limits to run solution
+// with (nil if sandbox mode isn't enabled)
+func (opt Opts) sandbox() *cln.Sandbox {
+	if opt.Sandbox == false {
+		return nil
+	}
+	return &cln.Sandbox{
+		CPUTime: uint64(math.Ceil(opt.Tl)) + 1,
+		// virtual memory is usually larger than resident memory
+		Memory:   2 * uint64(opt.MemLim),
+		FileSize: 64,
+		Procs:    1,
+	}
+}
+
+// findChecker returns script of checker to validate output with.
+// Checker in problem folder overrides template checker. Checker
+// isn't run through shell, as test files are passed as arguments
+func findChecker(t cfg.Template, e Env) string {
+	if checker := cln.FindProgram("checker"); checker != "" {
+		return checker
+	} else if t.Checker != "" {
+		return e.ReplScript(t.Checker, false)
+	}
+	return ""
+}
+
+// checkInput runs validator of problem (if present) on inp.
+// Returns verdict (FAIL if validator failed, INVALID if input
+// is rejected, else empty) and message of validator
+func checkInput(inp string) (string, string) {
+	validator := cln.FindProgram("validator")
+	if validator == "" {
+		return "", ""
+	}
+	valid, msg, err := cln.ExecValidator(validator, inp)
+	switch {
+	case err != nil:
+		return "FAIL", err.Error()
+	case valid == false:
+		return "INVALID", msg
+	}
+	return "", ""
+}
+
+// checkOutput validates output against answer with checker (if
+// given), else compares the outputs based on validator flags
+// Returns verdict (AC, WA, PE, FAIL) and message of checker
+func (opt Opts) checkOutput(checker, inp, out, ans string) (string, string, error) {
+	if checker != "" {
+		return cln.ExecChecker(checker, inp, out, ans)
+	}
+	out, ans = cln.Validator(out, ans, opt.IgCase)
+	if cln.Compare(out, ans, opt.equal) == false {
+		return "WA", "", nil
+	}
+	return "AC", "", nil
+}
+
+// note formats (non-empty) message to append to verdict
+func note(msg string) string {
+	if msg == "" {
+		return ""
+	}
+	return " (" + msg + ")"
+}
+
+// result holds the judging data of a test
+type result struct {
+	Test    string
+	Verdict string
+	Usage   cln.Usage
+	// error / checker message
+	Message  string
+	ExitCode int
+
+	Input, Output, Answer, Stderr string
+}
+
+// print writes verdict of result (and diff if WA) to w
+func (r result) print(w io.Writer, opt Opts) {
+	switch r.Verdict {
+	case "TLE":
+		pkg.Yellow.Fprintf(w, "#%v: TLE .... %v\n", r.Test, r.Usage.String())
+
+	case "MLE":
+		pkg.Red.Fprintf(w, "#%v: MLE .... %v\n", r.Test, r.Usage.String())
+
+	case "OLE", "SV":
+		// print output limit / security violation message
+		pkg.Red.Fprintf(w, "#%v: %v .... %v\n", r.Test, r.Verdict, r.Message)
+
+	case "RTE":
+		// print RTE message with error data
+		pkg.Red.Fprintf(w, "#%v: RTE .... %v\n", r.Test, r.Message)
+
+	case "FAIL":
+		// print FAIL message (checker failed)
+		pkg.Yellow.Fprintf(w, "#%v: FAIL .... %v\n", r.Test, r.Message)
+
+	case "INVALID":
+		// input rejected by validator (not judged)
+		pkg.Yellow.Fprintf(w, "#%v: INVALID INPUT%v\n", r.Test, note(r.Message))
+
+	case "NA":
+		// print output, as no answer exists to validate with
+		pkg.Blue.Fprintf(w, "#%v: NA .... %v (no answer)\n", r.Test, r.Usage.String())
+		fmt.Fprintln(w, r.Output)
+
+	case "AC":
+		pkg.Green.Fprintf(w, "#%v: AC .... %v%v\n", r.Test, r.Usage.String(), note(r.Message))
+
+	default:
+		// print verdict with checker message (if any)
+		pkg.Red.Fprintf(w, "#%v: %v .... %v%v\n", r.Test, r.Verdict,
+			r.Usage.String(), note(r.Message))
+		if diff := r.diff(opt); diff != "" {
+			// print diff output
+			pkg.Blue.Fprintln(w, diff)
+		}
+	}
+	opt.printStderr(w, r.Stderr)
+}
+
+// printStderr writes captured stderr of a test
+// to w, if --stderr is set to show
+func (opt Opts) printStderr(w io.Writer, stderr string) {
+	if opt.Stderr != "show" || stderr == "" {
+		return
+	}
+	pkg.Blue.Fprintln(w, "stderr:")
+	fmt.Fprint(w, stderr)
+	if strings.HasSuffix(stderr, "\n") == false {
+		fmt.Fprintln(w)
+	}
+}
+
+// saveStderr writes stderr of test to file <name>.err (stale
+// file is removed if stderr is empty), if --stderr is set to file
+func (opt Opts) saveStderr(name, stderr string) {
+	if opt.Stderr != "file" {
+		return
+	}
+	file := name + ".err"
+	if stderr == "" {
+		os.Remove(file)
+	} else if err := ioutil.WriteFile(file, []byte(stderr), 0644); err != nil {
+		pkg.Log.Warning("Failed to save stderr of test " + name)
+	}
+}
+
+// diff returns diff of output and expected output
+// Empty if not WA, or output validated by checker
+func (r result) diff(opt Opts) string {
+	if r.Verdict != "WA" || r.Message != "" {
+		return ""
+	}
+	out, ans := cln.Validator(r.Output, r.Answer, opt.IgCase)
+	return cln.PrintDiff(r.Input, out, ans, opt.Diff, opt.equal)
+}
+
+// equal reports whether tokens of output and expected
+// output are equal, within absolute / relative error
+func (opt Opts) equal(out, ans string) bool {
+	return cln.EqualTokens(out, ans, opt.AbsEps, opt.RelEps)
 }
 
 // tradJudge is the traditional judging process of running
 // source code against input and comparing with reqd output
 func (opt Opts) tradJudge(t cfg.Template, e Env) {
 	// fetch test cases from current directory
-	inp, out, err := cln.FindTests()
+	tests, err := cln.FindTests()
 	pkg.PrintError(err, "Failed to parse sample tests")
+	tests, err = opt.selectTests(tests)
+	pkg.PrintError(err, "Failed to select tests")
+	if len(tests) == 0 && opt.Failed == true {
+		pkg.Log.Success("No failed tests in last run")
+		return
+	}
+	results := opt.runTests(t, e, tests)
 
-	// run judge for each test file
-	for i := 0; i < len(inp); i++ {
-		// replace placeholders in script
-		script := e.ReplPlaceholder(t.Script)
-		// run script and calc time taken
-		elapsed, stdout, err := cln.ExecScript(script, inp[i], opt.Tl)
-		stdout, out[i] = cln.Validator(stdout, out[i], opt.IgCase, opt.Exp)
-		// todo : add functionality to return json string of verdict
-		switch {
-		case elapsed.Seconds() >= float64(opt.Tl):
-			// print TLE message (add support for custom time limit)
-			pkg.Yellow.Printf("#%d: TLE .... %v\n", i, elapsed.String())
+	// collect results in order of tests
+	var data []result
+	for i := range results {
+		r := <-results[i]
+		if opt.Report == "" {
+			// print verdicts as tests finish
+			r.print(os.Stdout, opt)
+		}
+		data = append(data, r)
+	}
+	saveState(data)
 
-		case err != nil:
-			// print RTE message with error data
-			pkg.Red.Printf("#%d: RTE .... %v\n", i, err.Error())
+	// print report of results (if specified)
+	if opt.Report != "" {
+		report, err := opt.report(data)
+		pkg.PrintError(err, "Failed to generate report")
+		fmt.Println(report)
+	}
+	return
+}
+
+// selectTests filters tests to run, to those specified by
+// --only or to those that failed in the last run (--failed)
+func (opt Opts) selectTests(tests []cln.Test) ([]cln.Test, error) {
+	names := make(map[string]bool)
+	if opt.Only != "" {
+		for _, name := range strings.Split(opt.Only, ",") {
+			names[strings.TrimSpace(name)] = true
+		}
+	} else if opt.Failed == true {
+		state, err := cln.LoadState()
+		if os.IsNotExist(err) {
+			pkg.Log.Notice("No previous test run found. Running all tests")
+			return tests, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for _, name := range state.Failed {
+			names[name] = true
+		}
+	} else {
+		return tests, nil
+	}
 
-		case stdout != out[i]:
-			// print WA message and diff output
-			pkg.Red.Printf("#%d: WA .... %v\n", i, elapsed.String())
-			diff := cln.PrintDiff(inp[i], stdout, out[i])
-			pkg.Log.Info(diff)
+	var sel []cln.Test
+	for _, test := range tests {
+		if names[test.Name] == true {
+			sel = append(sel, test)
+			delete(names, test.Name)
+		}
+	}
+	// all tests specified in --only must exist
+	if opt.Only != "" && len(names) != 0 {
+		var missing []string
+		for name := range names {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return nil, fmt.Errorf("Test(s) %v not found", strings.Join(missing, ", "))
+	}
+	return sel, nil
+}
 
+// saveState records names of failed tests (not AC / NA) to the
+// state file. Failures of tests that weren't run are retained
+func saveState(results []result) {
+	ran := make(map[string]bool)
+	for _, r := range results {
+		ran[r.Test] = true
+	}
+	state, _ := cln.LoadState()
+	failed := []string{}
+	for _, name := range state.Failed {
+		if ran[name] == false {
+			failed = append(failed, name)
+		}
+	}
+	for _, r := range results {
+		if r.Verdict != "AC" && r.Verdict != "NA" {
+			failed = append(failed, r.Test)
+		}
+	}
+
+	state.Failed = failed
+	if err := cln.SaveState(state); err != nil {
+		pkg.Log.Warning("Failed to save test state: " + err.Error())
+	}
+}
+
+// runTests judges tests with a pool of workers, and returns
+// channels to which result of each test is sent (in order)
+func (opt Opts) runTests(t cfg.Template, e Env, tests []cln.Test) []chan result {
+	// find checker of tests (if any)
+	checker := findChecker(t, e)
+	// replace placeholders in script
+	script := e.ReplScript(t.Script, t.Shell)
+
+	// judges test and returns the result
+	judge := func(test cln.Test) result {
+		r := result{Test: test.Name, Input: test.Input, Answer: test.Answer}
+		// skip tests with input rejected by validator
+		if verdict, msg := checkInput(test.Input); verdict != "" {
+			r.Verdict, r.Message = verdict, msg
+			return r
+		}
+		// run script and calc resources used
+		usage, stdout, stderr, err := cln.ExecScript(script, test.Input, opt.timeout(), opt.sandbox())
+		r.Usage, r.Output, r.Stderr = usage, stdout, stderr
+		opt.saveStderr(test.Name, stderr)
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			r.ExitCode = exitErr.ExitCode()
+		}
+		switch {
+		case opt.isTLE(usage):
+			r.Verdict = "TLE"
+		case usage.Memory > int64(opt.MemLim)*1024:
+			r.Verdict = "MLE"
+		case err == cln.ErrOutputLimit:
+			r.Verdict, r.Message = "OLE", err.Error()
+		case err == cln.ErrSecurity:
+			r.Verdict, r.Message = "SV", err.Error()
+		case err != nil:
+			r.Verdict, r.Message = "RTE", cln.ExitReason(err)
+		case test.AnsFile == "":
+			r.Verdict = "NA"
 		default:
-			// print AC message
-			pkg.Green.Printf("#%d: AC .... %v\n", i, elapsed.String())
+			r.Verdict, r.Message, err = opt.checkOutput(checker, r.Input, r.Output, r.Answer)
+			if err != nil {
+				r.Verdict, r.Message = "FAIL", err.Error()
+			}
 		}
+		return r
 	}
-	return
+
+	// number of tests to run concurrently, capped by cpu
+	// count, to ensure fair measurement of time taken
+	jobs := opt.Jobs
+	if jobs > runtime.NumCPU() {
+		jobs = runtime.NumCPU()
+	} else if jobs < 1 {
+		jobs = 1
+	}
+	// result of each test is sent to its channel
+	results := make([]chan result, len(tests))
+	queue := make(chan int, len(tests))
+	for i := range tests {
+		results[i] = make(chan result, 1)
+		queue <- i
+	}
+	close(queue)
+	// run pool of workers judging tests from queue
+	for w := 0; w < jobs; w++ {
+		go func() {
+			for i := range queue {
+				results[i] <- judge(tests[i])
+			}
+		}()
+	}
+	return results
+}
+
+// watchJudge re-runs prescript and the tests, every time
+// the source file or test files are modified (till interrupt)
+func (opt Opts) watchJudge(t cfg.Template, e Env) {
+	// run postscript on interrupt (Ctrl+C)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	pkg.LiveUI.Start()
+	for last := ""; ; {
+		// check for modifications every half second
+		if curr := snapshot(e.File); curr == last {
+			select {
+			case <-interrupt:
+				pkg.LiveUI.Print()
+				runScript(t.PostScript, t.Shell, e)
+				return
+			case <-time.After(500 * time.Millisecond):
+				continue
+			}
+		} else {
+			last = curr
+		}
+
+		var data strings.Builder
+		pkg.Blue.Fprintf(&data, "Watching %v for changes (Ctrl+C to exit)\n\n", e.File)
+		pkg.LiveUI.Print(data.String() + "Running tests...")
+
+		// run prescript (display compilation errors inline)
+		if out, err := compile(t, e); err != nil {
+			compileError(&data, out, err)
+			pkg.LiveUI.Print(data.String())
+			continue
+		}
+		// run tests and display verdicts
+		tests, err := cln.FindTests()
+		if err == nil {
+			tests, err = opt.selectTests(tests)
+		}
+		if err != nil {
+			pkg.Red.Fprintln(&data, err.Error())
+		}
+		for _, result := range opt.runTests(t, e, tests) {
+			r := <-result
+			r.print(&data, opt)
+		}
+		pkg.LiveUI.Print(data.String())
+	}
+}
+
+// snapshot returns modification data of source file and
+// all files matching configured test file patterns
+func snapshot(file string) string {
+	files := []string{file}
+	for _, pattern := range cfg.Settings.TestPatterns {
+		inp, _ := filepath.Glob(pattern.Input)
+		out, _ := filepath.Glob(pattern.Output)
+		files = append(append(files, inp...), out...)
+	}
+
+	var data strings.Builder
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintln(&data, file, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return data.String()
 }
 
 func (opt Opts) spclJudge(t cfg.Template, e Env) {
+	// judge against interactor (if present)
+	if interactor := cln.FindProgram("interactor"); interactor != "" {
+		opt.interJudge(t, e, interactor)
+		return
+	}
 	// run script in terminal
-	script := e.ReplPlaceholder(t.Script)
-	cmds := strings.Split(script, " ")
-	cmd := exec.Command(cmds[0], cmds[1:]...)
+	script := e.ReplScript(t.Script, t.Shell)
+	cmd, err := cln.Command(context.Background(), script)
+	pkg.PrintError(err, "Failed to parse script")
 	// set stdin / stdout / stderr
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
@@ -120,3 +622,84 @@ func (opt Opts) spclJudge(t cfg.Template, e Env) {
 
 	return
 }
+
+// interJudge runs source code against interactor for each
+// input file, with verdict determined by the interactor
+func (opt Opts) interJudge(t cfg.Template, e Env, interactor string) {
+	// fetch test cases from current directory
+	tests, err := cln.FindTests()
+	pkg.PrintError(err, "Failed to parse sample tests")
+	tests, err = opt.selectTests(tests)
+	pkg.PrintError(err, "Failed to select tests")
+
+	// find checker of tests (if any)
+	checker := findChecker(t, e)
+
+	// run judge for each test file
+	var data []result
+	for _, test := range tests {
+		i := test.Name
+		// skip tests with input rejected by validator
+		if verdict, msg := checkInput(test.Input); verdict != "" {
+			r := result{Test: i, Verdict: verdict, Message: msg}
+			r.print(os.Stdout, opt)
+			data = append(data, r)
+			continue
+		}
+		// replace placeholders in script
+		script := e.ReplScript(t.Script, t.Shell)
+		res, err := cln.ExecInteractor(script, interactor, test.Input, test.Answer, opt.timeout())
+		if err == nil && opt.isTLE(res.Usage) {
+			res.Verdict = "TLE"
+		} else if err == nil && res.Usage.Memory > int64(opt.MemLim)*1024 {
+			res.Verdict = "MLE"
+		}
+		if err == nil && res.Verdict == "AC" && checker != "" && test.AnsFile != "" {
+			// validate output of interactor with checker
+			res.Verdict, res.Message, err = cln.ExecChecker(checker, test.Input, res.Output, test.Answer)
+		}
+
+		if err != nil {
+			res.Verdict, res.Message = "FAIL", err.Error()
+		}
+		data = append(data, result{Test: i, Verdict: res.Verdict, Usage: res.Usage,
+			Message: res.Message, Input: test.Input, Output: res.Output,
+			Answer: test.Answer, Stderr: res.Stderr})
+		opt.saveStderr(i, res.Stderr)
+		if opt.Report != "" {
+			// results are printed in report
+			continue
+		}
+
+		switch {
+		case err != nil:
+			pkg.Yellow.Printf("#%v: FAIL .... %v\n", i, err.Error())
+
+		case res.Verdict == "TLE":
+			pkg.Yellow.Printf("#%v: TLE .... %v\n", i, res.Usage.String())
+			pkg.Log.Info(cln.PrintTranscript(test.Input, res.Transcript))
+
+		case res.Verdict == "MLE":
+			pkg.Red.Printf("#%v: MLE .... %v\n", i, res.Usage.String())
+
+		case res.Verdict == "AC":
+			pkg.Green.Printf("#%v: AC .... %v%v\n", i, res.Usage.String(), note(res.Message))
+
+		default:
+			// print verdict with message and transcript
+			pkg.Red.Printf("#%v: %v .... %v%v\n", i, res.Verdict,
+				res.Usage.String(), note(res.Message))
+			pkg.Log.Info(cln.PrintTranscript(test.Input, res.Transcript))
+		}
+		opt.printStderr(os.Stdout, res.Stderr)
+	}
+	saveState(data)
+
+	// print report of results (if specified)
+	if opt.Report != "" {
+		report, err := opt.report(data)
+		pkg.PrintError(err, "Failed to generate report")
+		fmt.Println(report)
+	}
+	return
+}
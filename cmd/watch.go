@@ -2,6 +2,7 @@ package cmd
 
 import (
 	cln "cf/client"
+	cfg "cf/config"
 	pkg "cf/packages"
 
 	"fmt"
@@ -19,6 +20,12 @@ func (opt Opts) RunWatch() {
 		pkg.Log.Error("No contest id found")
 		return
 	}
+	// submissions of acmsguru are listed by handle
+	if opt.contClass == "acmsguru" && opt.SubCnt != 0 && cfg.Session.Handle == "" {
+		pkg.Log.Error("No login details configured")
+		pkg.Log.Notice("Configure login details through 'cf config'")
+		return
+	}
 	// header formatting for table
 	headerfmt := pkg.Blue.Add(color.Underline).SprintfFunc()
 
@@ -72,7 +79,7 @@ func (opt Opts) RunWatch() {
 			// timer to fetch data in interval of 1 second
 			start := time.Now()
 			// fetch contest submission status
-			data, err := cln.WatchSubmissions(opt.contest, opt.problem, opt.link)
+			data, err := cln.WatchSubmissions(opt.contest, opt.pageID(opt.problem), opt.statusLink())
 			pkg.PrintError(err, "Failed to extract submissions in contest")
 
 			// create new table
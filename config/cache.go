@@ -0,0 +1,14 @@
+package cfg
+
+import (
+	"os"
+)
+
+// CacheDir is the folder holding cached build files
+var CacheDir string
+
+// InitCache sets (and creates) the build cache folder
+func InitCache(path string) {
+	CacheDir = path
+	os.MkdirAll(CacheDir, os.ModePerm)
+}
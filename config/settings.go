@@ -8,13 +8,22 @@ import (
 	"os"
 )
 
+// TestPattern holds glob patterns of test input files and
+// their answer files. The '*' in Output pattern is replaced
+// by the value matched by '*' in Input pattern
+type TestPattern struct {
+	Input  string `json:"input"`
+	Output string `json:"output"`
+}
+
 // Settings holds configured settings data of the tool
 var Settings struct {
-	DfltTmplt  int    `json:"default_template"`
-	GenOnFetch bool   `json:"gen_on_fetch"`
-	Host       string `json:"host"`
-	Proxy      string `json:"proxy"`
-	WSName     string `json:"workspace_name"`
+	DfltTmplt    int           `json:"default_template"`
+	GenOnFetch   bool          `json:"gen_on_fetch"`
+	Host         string        `json:"host"`
+	Proxy        string        `json:"proxy"`
+	WSName       string        `json:"workspace_name"`
+	TestPatterns []TestPattern `json:"test_patterns"`
 }
 
 var settPath string
@@ -26,6 +35,12 @@ func init() {
 	Settings.Host = "https://codeforces.com"
 	Settings.Proxy = ""
 	Settings.WSName = "codeforces"
+	// first pattern is used to create new tests
+	Settings.TestPatterns = []TestPattern{
+		{Input: "*.in", Output: "*.out"},
+		{Input: "*.in", Output: "*.ans"},
+		{Input: "input/*", Output: "output/*"},
+	}
 }
 
 // InitSettings reads settings.json file
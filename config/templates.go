@@ -18,6 +18,10 @@ type Template struct {
 	PreScript  string `json:"pre_script"`
 	Script     string `json:"script"`
 	PostScript string `json:"post_script"`
+	Checker    string `json:"checker"`
+	Shell      bool   `json:"shell"`
+	// multiplier of time limit of tests
+	TimeFactor float64 `json:"time_factor"`
 }
 
 // Templates holds all configured templates of user